@@ -1,3 +1,6 @@
+//go:build ignore
+
+// 独立的接口调试程序，需要先启动服务器，不参与构建和 go test
 package main
 
 import (
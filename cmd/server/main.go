@@ -1,9 +1,11 @@
 package main
 
 import (
-	"html/template"
+	"crypto/tls"
 	"log"
+	"net/http"
 	"static-hosting-server/internal/api"
+	"static-hosting-server/internal/app"
 	"static-hosting-server/internal/config"
 	"static-hosting-server/internal/database"
 	"static-hosting-server/internal/scheduler"
@@ -25,6 +27,9 @@ func main() {
 		log.Fatal("Failed to initialize database:", err)
 	}
 
+	// 创建API、后台和定时任务共享的服务
+	application := app.New(cfg, db)
+
 	// 设置 Gin 模式
 	if cfg.Server.Mode == "release" {
 		gin.SetMode(gin.ReleaseMode)
@@ -33,22 +38,41 @@ func main() {
 	// 创建路由器
 	router := gin.Default()
 
-	// 设置自定义模板函数
-	router.SetFuncMap(template.FuncMap{
-		"add":      func(a, b int) int { return a + b },
-		"safeHTML": func(s string) template.HTML { return template.HTML(s) },
-	})
+	// 加载后台模板（内置模板嵌入在二进制文件中，调试模式下热加载磁盘模板）
+	themeManager := application.Themes
+	if err := themeManager.LoadAdminTemplates(router); err != nil {
+		log.Fatal("Failed to load templates:", err)
+	}
 
 	// 静态文件服务
 	router.Static("/static", "./static")
-	router.LoadHTMLGlob("templates/*")
+	router.GET("/themes/:name/assets/*filepath", themeManager.AssetsHandler())
 
 	// 设置路由
-	api.SetupRoutes(router, db, cfg)
-	web.SetupRoutes(router, db, cfg)
+	api.SetupRoutes(router, application)
+	web.SetupRoutes(router, application)
 
-	// 启动定时任务
-	scheduler.Start(db)
+	// 启动定时任务和后台任务队列
+	scheduler.Start(application)
+	application.Jobs.Start()
+
+	// 启动HTTPS服务，按SNI加载自定义域名的证书
+	if cfg.Server.HTTPSPort != "" {
+		domainService := application.Domains
+		tlsServer := &http.Server{
+			Addr:    ":" + cfg.Server.HTTPSPort,
+			Handler: router,
+			TLSConfig: &tls.Config{
+				GetCertificate: domainService.GetCertificate,
+			},
+		}
+		go func() {
+			log.Printf("HTTPS server starting on port %s", cfg.Server.HTTPSPort)
+			if err := tlsServer.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+				log.Printf("HTTPS server stopped: %v", err)
+			}
+		}()
+	}
 
 	// 启动服务器
 	log.Printf("Server starting on port %s", cfg.Server.Port)
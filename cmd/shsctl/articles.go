@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"static-hosting-server/internal/models"
+	"static-hosting-server/internal/services"
+	"text/tabwriter"
+)
+
+func articlesCommand(e *env, args []string) error {
+	name, args, err := subcommand(args, "articles")
+	if err != nil {
+		return err
+	}
+	articleService := e.Articles
+
+	switch name {
+	case "list":
+		flags := flag.NewFlagSet("articles list", flag.ExitOnError)
+		status := flags.String("status", "", "按状态过滤")
+		limit := flags.Int("limit", 0, "最多列出的数量，0 表示全部")
+		var site siteFlag
+		flags.Var(&site, "site", "只列出该站点的文章（0 为默认站点）")
+		flags.Parse(args)
+
+		articles, err := listArticles(articleService.ForSite(site.id), *status, *limit)
+		if err != nil {
+			return err
+		}
+		e.print(articles, "ID\tSITE\tSTATUS\tSLUG\tEXPIRES\tTITLE", func(w *tabwriter.Writer) {
+			for _, article := range articles {
+				fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n", article.ID, article.SiteID, article.Status,
+					article.Slug, formatTime(article.ExpiresAt), article.Title)
+			}
+		})
+		return nil
+
+	case "publish", "expire":
+		flags := flag.NewFlagSet("articles "+name, flag.ExitOnError)
+		flags.Parse(args)
+		id, err := singleArg(flags, "article ID")
+		if err != nil {
+			return err
+		}
+
+		var article *models.Article
+		if name == "publish" {
+			article, err = articleService.UpdateArticle(id, services.ArticleInput{Status: "published"})
+		} else {
+			// 按文章的过期策略处理
+			article, err = articleService.ExpireArticle(id)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s article %s: %w", name, id, err)
+		}
+		e.print(article, "", func(w *tabwriter.Writer) {
+			fmt.Fprintf(w, "Article %s is now %s\n", article.ID, article.Status)
+			if article.Status == "published" || article.Status == "archived" {
+				fmt.Fprintf(w, "URL:\t%s\n", articleService.PublicURL(article))
+			}
+		})
+		return nil
+	}
+	return fmt.Errorf("unknown articles subcommand '%s'", name)
+}
+
+// 按游标读取文章列表，limit为0时读取全部
+func listArticles(articleService *services.ArticleService, status string, limit int) ([]models.Article, error) {
+	var result []models.Article
+	opts := services.ArticleListOptions{Status: status, Limit: services.MaxPageSize}
+	cursor := ""
+	for {
+		articles, next, err := articleService.ListArticlesAfter(cursor, opts)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, articles...)
+		if limit > 0 && len(result) >= limit {
+			return result[:limit], nil
+		}
+		if next == "" {
+			return result, nil
+		}
+		cursor = next
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"text/tabwriter"
+)
+
+func jobsCommand(e *env, args []string) error {
+	name, args, err := subcommand(args, "jobs")
+	if err != nil {
+		return err
+	}
+
+	switch name {
+	case "failed":
+		flags := flag.NewFlagSet("jobs failed", flag.ExitOnError)
+		var site siteFlag
+		flags.Var(&site, "site", "只列出该站点的任务（0 为默认站点）")
+		flags.Parse(args)
+
+		jobs, err := e.Jobs.ListFailed(site.id)
+		if err != nil {
+			return err
+		}
+		e.print(jobs, "ID\tSITE\tTYPE\tSTATUS\tATTEMPTS\tUPDATED\tERROR", func(w *tabwriter.Writer) {
+			for _, job := range jobs {
+				fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%d/%d\t%s\t%s\n", job.ID, job.SiteID, job.Type, job.Status,
+					job.Attempts, job.MaxAttempts, formatTime(&job.UpdatedAt), job.LastError)
+			}
+		})
+		return nil
+
+	case "retry":
+		flags := flag.NewFlagSet("jobs retry", flag.ExitOnError)
+		flags.Parse(args)
+		value, err := singleArg(flags, "job ID")
+		if err != nil {
+			return err
+		}
+		id, err := parseID(value)
+		if err != nil {
+			return err
+		}
+
+		if err := e.Jobs.Retry(id); err != nil {
+			return fmt.Errorf("failed to retry job %d: %w", id, err)
+		}
+		e.print(map[string]interface{}{"retried": id}, "", func(w *tabwriter.Writer) {
+			fmt.Fprintf(w, "Job %d queued for retry\n", id)
+		})
+		return nil
+
+	case "run":
+		// 服务器未运行时在当前进程中执行队列中的任务
+		flags := flag.NewFlagSet("jobs run", flag.ExitOnError)
+		flags.Parse(args)
+
+		count, err := e.Jobs.RunDue()
+		if err != nil {
+			return err
+		}
+		e.print(map[string]interface{}{"ran": count}, "", func(w *tabwriter.Writer) {
+			fmt.Fprintf(w, "Ran %d jobs\n", count)
+		})
+		return nil
+	}
+	return fmt.Errorf("unknown jobs subcommand '%s'", name)
+}
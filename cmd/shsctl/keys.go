@@ -0,0 +1,118 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"text/tabwriter"
+	"time"
+)
+
+func keysCommand(e *env, args []string) error {
+	name, args, err := subcommand(args, "keys")
+	if err != nil {
+		return err
+	}
+	authService := e.Auth
+
+	switch name {
+	case "create":
+		flags := flag.NewFlagSet("keys create", flag.ExitOnError)
+		keyName := flags.String("name", "", "密钥名称")
+		permissions := flags.String("permissions", "", "权限（JSON）")
+		expires := flags.String("expires", "", "有效期，如 720h 或 2025-12-31")
+		defaultTTL := flags.Duration("default-ttl", 0, "通过此密钥创建的文章未指定过期时间时的有效期，如 720h（整小时）")
+		var site siteFlag
+		flags.Var(&site, "site", "只能访问该站点（0 为默认站点）")
+		flags.Parse(args)
+
+		if *keyName == "" {
+			return fmt.Errorf("-name is required")
+		}
+		expiresAt, err := parseExpires(*expires)
+		if err != nil {
+			return err
+		}
+		if *defaultTTL < 0 || *defaultTTL%time.Hour != 0 {
+			return fmt.Errorf("invalid -default-ttl '%s': must be a whole number of hours", *defaultTTL)
+		}
+
+		key, err := authService.GenerateAPIKey(*keyName, *permissions, expiresAt, site.id, int(*defaultTTL/time.Hour))
+		if err != nil {
+			return err
+		}
+		e.print(key, "", func(w *tabwriter.Writer) {
+			fmt.Fprintf(w, "ID:\t%d\nName:\t%s\nKey:\t%s\n", key.ID, key.Name, key.Key)
+		})
+		return nil
+
+	case "list":
+		flags := flag.NewFlagSet("keys list", flag.ExitOnError)
+		var site siteFlag
+		flags.Var(&site, "site", "只列出该站点的密钥")
+		flags.Parse(args)
+
+		keys, err := authService.ListAPIKeys(site.id)
+		if err != nil {
+			return err
+		}
+		e.print(keys, "ID\tNAME\tSITE\tACTIVE\tEXPIRES\tLAST USED", func(w *tabwriter.Writer) {
+			for _, key := range keys {
+				fmt.Fprintf(w, "%d\t%s\t%s\t%t\t%s\t%s\n", key.ID, key.Name, formatSite(key.SiteID),
+					key.IsActive, formatTime(key.ExpiresAt), formatTime(key.LastUsedAt))
+			}
+		})
+		return nil
+
+	case "revoke":
+		flags := flag.NewFlagSet("keys revoke", flag.ExitOnError)
+		flags.Parse(args)
+		value, err := singleArg(flags, "key ID")
+		if err != nil {
+			return err
+		}
+		id, err := parseID(value)
+		if err != nil {
+			return err
+		}
+
+		if err := authService.RevokeAPIKey(id); err != nil {
+			return fmt.Errorf("failed to revoke key %d: %w", id, err)
+		}
+		e.print(map[string]interface{}{"id": id, "revoked": true}, "", func(w *tabwriter.Writer) {
+			fmt.Fprintf(w, "Revoked key %d\n", id)
+		})
+		return nil
+	}
+	return fmt.Errorf("unknown keys subcommand '%s'", name)
+}
+
+// 解析有效期：时长（从现在起）或日期
+func parseExpires(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		t := time.Now().Add(d)
+		return &t, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid -expires '%s': use a duration like 720h or a date like 2025-12-31", value)
+}
+
+func formatSite(siteID *uint) string {
+	if siteID == nil {
+		return "*"
+	}
+	return fmt.Sprintf("%d", *siteID)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04")
+}
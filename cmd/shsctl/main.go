@@ -0,0 +1,160 @@
+// shsctl 是服务器的命令行管理工具，直接操作数据库，适合在容器中编写脚本使用。
+//
+//	shsctl [-json] <命令> [子命令] [参数]
+//
+// 配置的读取方式与服务器相同（configs/config.yml 和 SHS_ 前缀的环境变量）。
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"static-hosting-server/internal/app"
+	"static-hosting-server/internal/config"
+	"static-hosting-server/internal/database"
+	"strconv"
+	"text/tabwriter"
+
+	"gorm.io/gorm/logger"
+)
+
+const usage = `用法: shsctl [-json] <命令> [子命令] [参数]
+
+命令:
+  keys create -name <名称> [-site <站点ID>] [-expires <时长或日期>] [-default-ttl <时长>] [-permissions <权限>]
+  keys list [-site <站点ID>]
+  keys revoke <密钥ID>
+
+  users create -username <用户名> -email <邮箱> [-password <密码>] [-site <站点ID>]
+  users passwd -username <用户名> [-password <密码>]
+  users list
+
+  articles list [-site <站点ID>] [-status <状态>] [-limit <数量>]
+  articles publish <文章ID>
+  articles expire <文章ID>
+
+  jobs failed [-site <站点ID>]  列出失败和等待重试的后台任务
+  jobs retry <任务ID>
+  jobs run                    在当前进程中执行已到期的任务
+
+  rebuild [-site <站点ID>]    重新生成静态文件
+  migrate                     迁移数据库表结构
+
+未指定 -password 时从标准输入读取一行作为密码。
+发布、过期等操作的静态文件由服务器的后台任务生成，服务器未运行时可执行 jobs run。
+`
+
+// 命令执行环境
+type env struct {
+	*app.App
+	json bool
+}
+
+func main() {
+	log.SetFlags(0)
+
+	flags := flag.NewFlagSet("shsctl", flag.ExitOnError)
+	jsonOutput := flags.Bool("json", false, "以JSON格式输出")
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flags.Parse(os.Args[1:])
+
+	args := flags.Args()
+	if len(args) == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	commands := map[string]func(*env, []string) error{
+		"keys":     keysCommand,
+		"users":    usersCommand,
+		"articles": articlesCommand,
+		"jobs":     jobsCommand,
+		"rebuild":  rebuildCommand,
+		"migrate":  migrateCommand,
+	}
+	command, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "未知命令: %s\n\n", args[0])
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Failed to load config: ", err)
+	}
+	db, err := database.Connect(cfg.Database, logger.Warn)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := command(&env{App: app.New(cfg, db), json: *jsonOutput}, args[1:]); err != nil {
+		log.Fatal("Error: ", err)
+	}
+}
+
+// 取出子命令
+func subcommand(args []string, name string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("missing %s subcommand, run shsctl -h for usage", name)
+	}
+	return args[0], args[1:], nil
+}
+
+// 读取唯一的位置参数
+func singleArg(flags *flag.FlagSet, name string) (string, error) {
+	if flags.NArg() != 1 {
+		return "", fmt.Errorf("expected exactly one %s", name)
+	}
+	return flags.Arg(0), nil
+}
+
+// 解析数字ID
+func parseID(value string) (uint, error) {
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("invalid ID '%s'", value)
+	}
+	return uint(id), nil
+}
+
+// 站点参数，0 表示默认站点，未指定时为nil
+type siteFlag struct {
+	id *uint
+}
+
+func (f *siteFlag) String() string {
+	if f.id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*f.id), 10)
+}
+
+func (f *siteFlag) Set(value string) error {
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid site ID '%s'", value)
+	}
+	site := uint(id)
+	f.id = &site
+	return nil
+}
+
+// 输出结果：-json 时输出JSON，否则按表格输出
+func (e *env) print(value interface{}, header string, rows func(w *tabwriter.Writer)) {
+	if e.json {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(value)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	if header != "" {
+		fmt.Fprintln(w, header)
+	}
+	rows(w)
+	w.Flush()
+}
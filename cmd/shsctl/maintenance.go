@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"static-hosting-server/internal/database"
+	"text/tabwriter"
+)
+
+// 重新生成静态文件，未指定站点时处理所有站点
+func rebuildCommand(e *env, args []string) error {
+	flags := flag.NewFlagSet("rebuild", flag.ExitOnError)
+	var site siteFlag
+	flags.Var(&site, "site", "只处理该站点（0 为默认站点）")
+	flags.Parse(args)
+
+	rebuilt, err := e.Articles.ForSite(site.id).RebuildStaticFiles()
+	if err != nil {
+		return err
+	}
+	e.print(map[string]interface{}{"rebuilt": rebuilt}, "", func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "Rebuilt %d articles\n", rebuilt)
+	})
+	return nil
+}
+
+// 迁移数据库表结构
+func migrateCommand(e *env, args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	flags.Parse(args)
+
+	if err := database.Migrate(); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	e.print(map[string]interface{}{"migrated": true}, "", func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "Database migrated")
+	})
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+func usersCommand(e *env, args []string) error {
+	name, args, err := subcommand(args, "users")
+	if err != nil {
+		return err
+	}
+	authService := e.Auth
+
+	switch name {
+	case "create":
+		flags := flag.NewFlagSet("users create", flag.ExitOnError)
+		username := flags.String("username", "", "用户名")
+		email := flags.String("email", "", "邮箱")
+		password := flags.String("password", "", "密码，省略时从标准输入读取")
+		var site siteFlag
+		flags.Var(&site, "site", "只能管理该站点（0 为默认站点）")
+		flags.Parse(args)
+
+		if err := readPassword(password); err != nil {
+			return err
+		}
+		user, err := authService.CreateAdmin(*username, *email, *password, site.id)
+		if err != nil {
+			return err
+		}
+		e.print(user, "", func(w *tabwriter.Writer) {
+			fmt.Fprintf(w, "Created admin %s (ID %d)\n", user.Username, user.ID)
+		})
+		return nil
+
+	case "passwd":
+		flags := flag.NewFlagSet("users passwd", flag.ExitOnError)
+		username := flags.String("username", "", "用户名")
+		password := flags.String("password", "", "新密码，省略时从标准输入读取")
+		flags.Parse(args)
+
+		if *username == "" {
+			return fmt.Errorf("-username is required")
+		}
+		if err := readPassword(password); err != nil {
+			return err
+		}
+		if err := authService.SetPassword(*username, *password); err != nil {
+			return err
+		}
+		e.print(map[string]interface{}{"username": *username, "updated": true}, "", func(w *tabwriter.Writer) {
+			fmt.Fprintf(w, "Password updated for %s\n", *username)
+		})
+		return nil
+
+	case "list":
+		flags := flag.NewFlagSet("users list", flag.ExitOnError)
+		flags.Parse(args)
+
+		users, err := authService.ListUsers()
+		if err != nil {
+			return err
+		}
+		e.print(users, "ID\tUSERNAME\tEMAIL\tROLE\tSITE", func(w *tabwriter.Writer) {
+			for _, user := range users {
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", user.ID, user.Username, user.Email, user.Role, formatSite(user.SiteID))
+			}
+		})
+		return nil
+	}
+	return fmt.Errorf("unknown users subcommand '%s'", name)
+}
+
+// 未通过参数指定密码时从标准输入读取一行
+func readPassword(password *string) error {
+	if *password != "" {
+		return nil
+	}
+
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		fmt.Fprint(os.Stderr, "Password: ")
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return fmt.Errorf("failed to read password from stdin: %w", err)
+	}
+	*password = strings.TrimRight(line, "\r\n")
+	return nil
+}
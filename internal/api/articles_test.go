@@ -0,0 +1,581 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"static-hosting-server/internal/events"
+	"static-hosting-server/internal/models"
+)
+
+func TestAPIKeyAuth(t *testing.T) {
+	e := newTestEnv(t)
+
+	e.request(http.MethodGet, "/api/articles", "", nil).expect(t, http.StatusUnauthorized)
+	e.request(http.MethodGet, "/api/articles", "wrong-key", nil).expect(t, http.StatusUnauthorized)
+	e.request(http.MethodGet, "/api/articles?api_key="+testAPIKey, "", nil).expect(t, http.StatusOK)
+
+	// 数据库中的密钥
+	var key models.APIKey
+	e.api(http.MethodPost, "/api/keys", map[string]interface{}{"name": "n8n"}).
+		expect(t, http.StatusCreated).decode(t, &key)
+	if len(key.Key) != 32 {
+		t.Fatalf("expected a 32 character key, got %q", key.Key)
+	}
+	e.request(http.MethodGet, "/api/articles", key.Key, nil).expect(t, http.StatusOK)
+
+	// 过期和停用的密钥
+	e.db.Model(&key).Update("expires_at", time.Now().Add(-time.Minute))
+	e.request(http.MethodGet, "/api/articles", key.Key, nil).expect(t, http.StatusUnauthorized)
+	e.db.Model(&key).Updates(map[string]interface{}{"expires_at": nil, "is_active": false})
+	e.request(http.MethodGet, "/api/articles", key.Key, nil).expect(t, http.StatusUnauthorized)
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	e := newTestEnv(t)
+	site, err := e.app.Sites.CreateSite("Docs", "", "", "")
+	if err != nil {
+		t.Fatalf("create site: %v", err)
+	}
+	var global, scoped models.APIKey
+	e.api(http.MethodPost, "/api/keys", map[string]interface{}{"name": "global"}).expect(t, http.StatusCreated).decode(t, &global)
+	e.api(http.MethodPost, "/api/keys", map[string]interface{}{"name": "docs", "site_id": site.ID}).
+		expect(t, http.StatusCreated).decode(t, &scoped)
+
+	// 列表不返回密钥本身，绑定站点的密钥只能看到本站点的密钥
+	var keys []models.APIKey
+	e.api(http.MethodGet, "/api/keys", nil).expect(t, http.StatusOK).decode(t, &keys)
+	if len(keys) != 2 || keys[0].Key != "" {
+		t.Fatalf("expected two keys without secrets, got %+v", keys)
+	}
+	e.request(http.MethodGet, "/api/keys", scoped.Key, nil).expect(t, http.StatusOK).decode(t, &keys)
+	if len(keys) != 1 || keys[0].ID != scoped.ID {
+		t.Fatalf("site key should only list its own site's keys, got %+v", keys)
+	}
+	e.request(http.MethodDelete, fmt.Sprintf("/api/keys/%d", global.ID), scoped.Key, nil).expect(t, http.StatusNotFound)
+
+	e.request(http.MethodGet, "/api/articles", global.Key, nil).expect(t, http.StatusOK)
+	e.api(http.MethodDelete, fmt.Sprintf("/api/keys/%d", global.ID), nil).expect(t, http.StatusOK)
+	e.request(http.MethodGet, "/api/articles", global.Key, nil).expect(t, http.StatusUnauthorized)
+	e.api(http.MethodDelete, "/api/keys/999", nil).expect(t, http.StatusNotFound)
+}
+
+func TestSiteScopedKey(t *testing.T) {
+	e := newTestEnv(t)
+
+	var site models.Site
+	e.api(http.MethodPost, "/api/sites", map[string]interface{}{"name": "docs"}).
+		expect(t, http.StatusCreated).decode(t, &site)
+	var key models.APIKey
+	e.api(http.MethodPost, "/api/keys", map[string]interface{}{"name": "docs", "site_id": site.ID}).
+		expect(t, http.StatusCreated).decode(t, &key)
+
+	other := e.createArticle(map[string]interface{}{"title": "Default site", "content": "<p>x</p>"})
+
+	// 绑定站点的密钥看不到其他站点的文章，创建的文章属于本站点
+	e.request(http.MethodGet, "/api/articles/"+other.ID, key.Key, nil).expect(t, http.StatusNotFound)
+	var own models.Article
+	e.request(http.MethodPost, "/api/articles", key.Key, map[string]interface{}{"title": "Docs", "content": "<p>y</p>"}).
+		expect(t, http.StatusCreated).decode(t, &own)
+	if own.SiteID != site.ID {
+		t.Fatalf("expected article in site %d, got %d", site.ID, own.SiteID)
+	}
+	e.request(http.MethodGet, "/api/sites", key.Key, nil).expect(t, http.StatusForbidden)
+}
+
+func TestSiteValidation(t *testing.T) {
+	e := newTestEnv(t)
+
+	// 存储前缀不能与默认站点或自动生成的目录重叠
+	for _, prefix := range []string{"articles", "site-3", "Bad_Prefix"} {
+		resp := e.api(http.MethodPost, "/api/sites", map[string]interface{}{"name": "x-" + prefix, "storage_prefix": prefix}).
+			expect(t, http.StatusUnprocessableEntity)
+		if resp.Errors["storage_prefix"] == "" {
+			t.Fatalf("expected a storage_prefix error for %q, got %v", prefix, resp.Errors)
+		}
+	}
+	var site models.Site
+	e.api(http.MethodPost, "/api/sites", map[string]interface{}{"name": "docs", "storage_prefix": "docs"}).
+		expect(t, http.StatusCreated).decode(t, &site)
+	e.api(http.MethodPost, "/api/sites", map[string]interface{}{"name": "docs-2", "storage_prefix": "docs"}).
+		expect(t, http.StatusConflict)
+
+	// 不存在的站点
+	e.api(http.MethodPost, "/api/keys", map[string]interface{}{"name": "ghost", "site_id": 99}).
+		expect(t, http.StatusUnprocessableEntity)
+	e.api(http.MethodPost, "/api/redirects", map[string]interface{}{"source_path": "/old", "target": "/new", "site_id": 99}).
+		expect(t, http.StatusUnprocessableEntity)
+	resp := e.api(http.MethodPost, "/api/blueprints", map[string]interface{}{"name": "ghost", "content": "<p>{{x}}</p>", "site_id": 99}).
+		expect(t, http.StatusUnprocessableEntity)
+	if resp.Errors["site_id"] == "" {
+		t.Fatalf("expected a site_id error, got %v", resp.Errors)
+	}
+
+	// 回收站中的文章和跳转同样阻止删除站点
+	article := e.createArticle(map[string]interface{}{"title": "Docs", "content": "<p>1</p>", "site_id": site.ID})
+	e.api(http.MethodDelete, "/api/articles/"+article.ID, nil).expect(t, http.StatusOK)
+	e.api(http.MethodPost, "/api/redirects", map[string]interface{}{"source_path": "/old", "target": "/new", "site_id": site.ID}).
+		expect(t, http.StatusCreated)
+	sitePath := fmt.Sprintf("/api/sites/%d", site.ID)
+	resp = e.api(http.MethodDelete, sitePath, nil).expect(t, http.StatusConflict)
+	if !strings.Contains(resp.Error, "articles") || !strings.Contains(resp.Error, "redirects") {
+		t.Fatalf("conflict should list the remaining data, got %q", resp.Error)
+	}
+
+	e.api(http.MethodDelete, "/api/trash/"+article.ID, nil).expect(t, http.StatusOK)
+	e.db.Where("site_id = ?", site.ID).Delete(&models.Redirect{})
+	e.api(http.MethodDelete, sitePath, nil).expect(t, http.StatusOK)
+}
+
+func TestSetSiteThemeQueuesRebuild(t *testing.T) {
+	e := newTestEnv(t)
+	e.cfg.Theme.Path = filepath.Join("..", "..", "themes")
+	site, err := e.app.Sites.CreateSite("Docs", "", "", "docs")
+	if err != nil {
+		t.Fatalf("create site: %v", err)
+	}
+	e.createArticle(map[string]interface{}{"title": "Guide", "content": "<p>1</p>", "slug": "guide", "status": "published", "site_id": site.ID})
+	e.createArticle(map[string]interface{}{"title": "Own theme", "content": "<p>2</p>", "slug": "own", "status": "published",
+		"site_id": site.ID, "theme": "default"})
+	e.runJobs()
+
+	// 主题设置后立即返回，页面由后台任务重新生成
+	var result struct {
+		Queued int `json:"queued"`
+	}
+	e.api(http.MethodPut, fmt.Sprintf("/api/sites/%d/theme", site.ID), map[string]interface{}{"theme": "minimal"}).
+		expect(t, http.StatusAccepted).decode(t, &result)
+	if result.Queued != 1 {
+		t.Fatalf("only the article using the site theme should be queued, got %d", result.Queued)
+	}
+
+	page := filepath.Join(e.cfg.Storage.StaticPath, "docs", "articles", "guide", "index.html")
+	if html, _ := os.ReadFile(page); strings.Contains(string(html), "/themes/minimal/") {
+		t.Fatal("pages should not be rebuilt inside the request")
+	}
+	e.runJobs()
+	if html, err := os.ReadFile(page); err != nil || !strings.Contains(string(html), "/themes/minimal/") {
+		t.Fatalf("page should be rebuilt with the new theme: %v", err)
+	}
+}
+
+func TestSitesShareSlug(t *testing.T) {
+	e := newTestEnv(t)
+	docs, err := e.app.Sites.CreateSite("Docs", "", "", "docs")
+	if err != nil {
+		t.Fatalf("create site: %v", err)
+	}
+	shop, err := e.app.Sites.CreateSite("Shop", "shop.example.test", "", "")
+	if err != nil {
+		t.Fatalf("create site: %v", err)
+	}
+	get := func(host, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Host = host
+		w := httptest.NewRecorder()
+		e.router.ServeHTTP(w, req)
+		return w
+	}
+
+	urls, ids := map[string]string{}, map[string]string{}
+	for name, siteID := range map[string]uint{"default": 0, "docs": docs.ID, "shop": shop.ID} {
+		resp := e.api(http.MethodPost, "/api/articles", map[string]interface{}{"title": "Hello", "content": "<p>" + name + " content</p>",
+			"slug": "hello", "status": "published", "site_id": siteID}).expect(t, http.StatusCreated)
+		var article models.Article
+		resp.decode(t, &article)
+		urls[name], ids[name] = resp.URL, article.ID
+	}
+	want := map[string]string{
+		"default": "http://blog.example.test/p/hello",
+		"docs":    "http://blog.example.test/s/docs/p/hello",
+		"shop":    "http://shop.example.test/p/hello",
+	}
+	for name, url := range want {
+		if urls[name] != url {
+			t.Fatalf("%s article should be published at %s, got %s", name, url, urls[name])
+		}
+	}
+
+	// 每个地址都返回所属站点的文章
+	for host, paths := range map[string]map[string]string{
+		"blog.example.test": {"/p/hello": "default", "/s/docs/p/hello": "docs"},
+		"shop.example.test": {"/p/hello": "shop"},
+	} {
+		for path, name := range paths {
+			if w := get(host, path); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), name+" content") {
+				t.Fatalf("%s%s should serve the %s article, got %d", host, path, name, w.Code)
+			}
+		}
+	}
+
+	// 已绑定域名的站点通过路径访问时跳转到站点域名，不存在的站点返回404
+	shopPath := fmt.Sprintf("/s/site-%d/p/hello", shop.ID)
+	if w := get("blog.example.test", shopPath); w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != want["shop"] {
+		t.Fatalf("site path of a site with a domain should redirect, got %d %s", w.Code, w.Header().Get("Location"))
+	}
+	if w := get("blog.example.test", "/s/missing/p/hello"); w.Code != http.StatusNotFound {
+		t.Fatalf("unknown site path should return 404, got %d", w.Code)
+	}
+
+	// 改名后旧地址跳转到站点路径下的新地址
+	e.api(http.MethodPut, "/api/articles/"+ids["docs"], map[string]interface{}{"slug": "hello-docs"}).expect(t, http.StatusOK)
+	if w := get("blog.example.test", "/s/docs/p/hello"); w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != "/s/docs/p/hello-docs" {
+		t.Fatalf("renamed article should redirect within the site path, got %d %s", w.Code, w.Header().Get("Location"))
+	}
+
+	// 未绑定站点的域名属于默认站点，不能用于其他站点的文章
+	var domain models.Domain
+	e.api(http.MethodPost, "/api/domains", map[string]interface{}{"host": "extra.example.test"}).
+		expect(t, http.StatusCreated).decode(t, &domain)
+	resp := e.api(http.MethodPost, "/api/articles", map[string]interface{}{"title": "Extra", "content": "<p>x</p>",
+		"site_id": docs.ID, "domain_id": domain.ID}).expect(t, http.StatusUnprocessableEntity)
+	if resp.Errors["domain_id"] == "" {
+		t.Fatalf("expected a domain_id error, got %v", resp.Errors)
+	}
+}
+
+func TestDomainURLScheme(t *testing.T) {
+	e := newTestEnv(t)
+	e.cfg.Storage.CertsPath = t.TempDir()
+	var domain models.Domain
+	e.api(http.MethodPost, "/api/domains", map[string]interface{}{"host": "news.example.test"}).
+		expect(t, http.StatusCreated).decode(t, &domain)
+	created := e.createArticle(map[string]interface{}{"title": "News", "content": "<p>1</p>", "domain_id": domain.ID})
+	articleURL := func() string {
+		article, err := e.app.Articles.GetArticleByID(created.ID)
+		if err != nil {
+			t.Fatalf("get article: %v", err)
+		}
+		return e.app.Articles.PublicURL(article)
+	}
+
+	// 待签发的证书记录不会启用HTTPS
+	e.cfg.Server.HTTPSPort = "8443"
+	if got := articleURL(); got != "http://news.example.test/p/news" {
+		t.Fatalf("pending certificate should keep http, got %s", got)
+	}
+
+	certDir := filepath.Join(e.cfg.Storage.CertsPath, "news.example.test")
+	writeCert := func(notAfter time.Time) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("generate key: %v", err)
+		}
+		template := &x509.Certificate{SerialNumber: big.NewInt(1), DNSNames: []string{"news.example.test"},
+			NotBefore: notAfter.Add(-90 * 24 * time.Hour), NotAfter: notAfter}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		if err != nil {
+			t.Fatalf("create certificate: %v", err)
+		}
+		keyDER, _ := x509.MarshalECPrivateKey(key)
+		os.MkdirAll(certDir, 0o755)
+		os.WriteFile(filepath.Join(certDir, "fullchain.pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644)
+		os.WriteFile(filepath.Join(certDir, "privkey.pem"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	}
+
+	writeCert(time.Now().Add(-time.Hour))
+	if got := articleURL(); got != "http://news.example.test/p/news" {
+		t.Fatalf("expired certificate should keep http, got %s", got)
+	}
+	writeCert(time.Now().Add(30 * 24 * time.Hour))
+	if got := articleURL(); got != "https://news.example.test:8443/p/news" {
+		t.Fatalf("issued certificate should switch to https, got %s", got)
+	}
+
+	// 未启用HTTPS服务时不使用https
+	e.cfg.Server.HTTPSPort = ""
+	if got := articleURL(); got != "http://news.example.test/p/news" {
+		t.Fatalf("https should require an HTTPS port, got %s", got)
+	}
+}
+
+func TestArticleCRUD(t *testing.T) {
+	e := newTestEnv(t)
+
+	created := e.createArticle(map[string]interface{}{
+		"title":   "Hello World",
+		"content": "<p>First post</p>",
+	})
+	if created.Slug != "hello-world" || created.Status != "draft" {
+		t.Fatalf("unexpected article: slug=%q status=%q", created.Slug, created.Status)
+	}
+
+	// 重名标题追加序号
+	second := e.createArticle(map[string]interface{}{"title": "Hello World", "content": "<p>Again</p>"})
+	if second.Slug != "hello-world-2" {
+		t.Fatalf("expected slug hello-world-2, got %q", second.Slug)
+	}
+
+	var fetched models.Article
+	e.api(http.MethodGet, "/api/articles/"+created.ID, nil).expect(t, http.StatusOK).decode(t, &fetched)
+	if fetched.Title != "Hello World" {
+		t.Fatalf("unexpected title %q", fetched.Title)
+	}
+
+	var updated models.Article
+	e.api(http.MethodPut, "/api/articles/"+created.ID, map[string]interface{}{"title": "Hello Again"}).
+		expect(t, http.StatusOK).decode(t, &updated)
+	if updated.Title != "Hello Again" || updated.Content != "<p>First post</p>" {
+		t.Fatalf("unexpected update result: %+v", updated)
+	}
+
+	var page struct {
+		Articles []models.Article `json:"articles"`
+		Total    int64            `json:"total"`
+	}
+	e.api(http.MethodGet, "/api/articles", nil).expect(t, http.StatusOK).decode(t, &page)
+	if page.Total != 2 || len(page.Articles) != 2 {
+		t.Fatalf("expected 2 articles, got total=%d len=%d", page.Total, len(page.Articles))
+	}
+
+	e.api(http.MethodDelete, "/api/articles/"+created.ID, nil).expect(t, http.StatusOK)
+	e.api(http.MethodGet, "/api/articles/"+created.ID, nil).expect(t, http.StatusNotFound)
+
+	e.api(http.MethodPost, "/api/articles", map[string]interface{}{"content": "<p>no title</p>"}).
+		expect(t, http.StatusUnprocessableEntity)
+}
+
+func TestArticleListPagination(t *testing.T) {
+	e := newTestEnv(t)
+	for i := 0; i < 5; i++ {
+		e.createArticle(map[string]interface{}{"title": "Post", "content": "<p>body</p>"})
+	}
+
+	resp := e.api(http.MethodGet, "/api/articles?page=2&limit=2&fields=id,title", nil).expect(t, http.StatusOK)
+	link := resp.Header.Get("Link")
+	for _, rel := range []string{`rel="first"`, `rel="prev"`, `rel="next"`, `rel="last"`} {
+		if !strings.Contains(link, rel) {
+			t.Fatalf("Link header %q is missing %s", link, rel)
+		}
+	}
+	var page struct {
+		Articles []map[string]interface{} `json:"articles"`
+	}
+	resp.decode(t, &page)
+	if len(page.Articles) != 2 || len(page.Articles[0]) != 2 {
+		t.Fatalf("expected 2 articles with 2 fields, got %v", page.Articles)
+	}
+
+	// 游标翻页不重复不遗漏
+	seen := make(map[string]bool)
+	cursor := ""
+	for requests := 0; ; requests++ {
+		if requests > 5 {
+			t.Fatal("cursor pagination did not terminate")
+		}
+		var result struct {
+			Articles   []models.Article `json:"articles"`
+			NextCursor string           `json:"next_cursor"`
+		}
+		e.api(http.MethodGet, "/api/articles?limit=2&cursor="+cursor, nil).expect(t, http.StatusOK).decode(t, &result)
+		for _, article := range result.Articles {
+			if seen[article.ID] {
+				t.Fatalf("article %s returned twice", article.ID)
+			}
+			seen[article.ID] = true
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		cursor = result.NextCursor
+	}
+	if len(seen) != 5 {
+		t.Fatalf("expected 5 articles, got %d", len(seen))
+	}
+
+	e.api(http.MethodGet, "/api/articles?cursor=bogus", nil).expect(t, http.StatusBadRequest)
+	e.api(http.MethodGet, "/api/articles?limit=0", nil).expect(t, http.StatusBadRequest)
+	e.api(http.MethodGet, "/api/articles?fields=password_hash", nil).expect(t, http.StatusBadRequest)
+}
+
+func TestPublishWritesStaticFiles(t *testing.T) {
+	e := newTestEnv(t)
+
+	draft := e.createArticle(map[string]interface{}{"title": "Draft", "content": "<p>wip</p>"})
+	if fileExists(e.staticFile(draft.Slug)) {
+		t.Fatal("draft should not have static files")
+	}
+
+	resp := e.api(http.MethodPost, "/api/articles", map[string]interface{}{
+		"title":   "Published",
+		"content": "<p>live content</p>",
+		"status":  "published",
+	}).expect(t, http.StatusCreated)
+	var article models.Article
+	resp.decode(t, &article)
+	if fileExists(e.staticFile(article.Slug)) {
+		t.Fatal("static files should be written by the job queue, not the request")
+	}
+	e.runJobs()
+	if resp.URL != "http://blog.example.test/p/published" {
+		t.Fatalf("unexpected public URL %q", resp.URL)
+	}
+
+	html, err := os.ReadFile(e.staticFile(article.Slug))
+	if err != nil {
+		t.Fatalf("static file not written: %v", err)
+	}
+	if !strings.Contains(string(html), "live content") {
+		t.Fatalf("static file does not contain the article content:\n%s", html)
+	}
+
+	// 改名后旧目录替换为跳转页
+	e.api(http.MethodPut, "/api/articles/"+article.ID, map[string]interface{}{"slug": "renamed"}).expect(t, http.StatusOK)
+	e.runJobs()
+	if !fileExists(e.staticFile("renamed")) {
+		t.Fatal("static file not written for the new slug")
+	}
+	stub, err := os.ReadFile(e.staticFile("published"))
+	if err != nil || !strings.Contains(string(stub), "/p/renamed") {
+		t.Fatalf("old slug should contain a redirect stub, got %q (%v)", stub, err)
+	}
+
+	// 删除后移除静态文件
+	e.api(http.MethodDelete, "/api/articles/"+article.ID, nil).expect(t, http.StatusOK)
+	e.runJobs()
+	if fileExists(e.staticFile("renamed")) {
+		t.Fatal("static files should be removed after delete")
+	}
+}
+
+func TestExpiryCleanup(t *testing.T) {
+	e := newTestEnv(t)
+	future := time.Now().Add(time.Hour)
+
+	unpublish := e.createArticle(map[string]interface{}{
+		"title": "Unpublish", "content": "<p>a</p>", "status": "published", "expires_at": future,
+	})
+	archive := e.createArticle(map[string]interface{}{
+		"title": "Archive", "content": "<p>b</p>", "status": "published", "expires_at": future,
+		"expiry_action": "archive",
+	})
+	redirect := e.createArticle(map[string]interface{}{
+		"title": "Redirect", "content": "<p>c</p>", "status": "published", "expires_at": future,
+		"expiry_action": "redirect", "redirect_url": "/p/archive",
+	})
+	e.runJobs()
+
+	e.db.Model(&models.Article{}).Where("1 = 1").Update("expires_at", time.Now().Add(-time.Minute))
+	if _, err := e.app.Articles.CleanupExpiredArticles(); err != nil {
+		t.Fatalf("cleanup: %v", err)
+	}
+	e.runJobs()
+
+	status := func(id string) string {
+		var article models.Article
+		e.db.First(&article, "id = ?", id)
+		return article.Status
+	}
+	if got := status(unpublish.ID); got != "expired" {
+		t.Fatalf("expected expired, got %q", got)
+	}
+	if fileExists(e.staticFile(unpublish.Slug)) {
+		t.Fatal("static files of expired article should be removed")
+	}
+	if got := status(archive.ID); got != "archived" {
+		t.Fatalf("expected archived, got %q", got)
+	}
+	if !fileExists(e.staticFile(archive.Slug)) {
+		t.Fatal("archived article should keep its static files")
+	}
+	if got := status(redirect.ID); got != "expired" {
+		t.Fatalf("expected expired, got %q", got)
+	}
+
+	e.request(http.MethodGet, "/p/"+unpublish.Slug, "", nil).expect(t, http.StatusGone)
+	e.request(http.MethodGet, "/p/"+archive.Slug, "", nil).expect(t, http.StatusOK)
+	resp := e.request(http.MethodGet, "/p/"+redirect.Slug, "", nil).expect(t, http.StatusFound)
+	if resp.Header.Get("Location") != "/p/archive" {
+		t.Fatalf("unexpected redirect target %q", resp.Header.Get("Location"))
+	}
+}
+
+func TestPublicRoute(t *testing.T) {
+	e := newTestEnv(t)
+
+	article := e.createArticle(map[string]interface{}{
+		"title":       "Public <Post>",
+		"content":     "<p>Hello readers</p>",
+		"slug":        "public-post",
+		"status":      "published",
+		"description": "A short summary",
+	})
+	draft := e.createArticle(map[string]interface{}{"title": "Hidden", "content": "<p>x</p>"})
+
+	resp := e.request(http.MethodGet, "/p/public-post", "", nil).expect(t, http.StatusOK)
+	for _, want := range []string{
+		"Hello readers",
+		"Public &lt;Post&gt;",
+		`<meta property="og:description" content="A short summary">`,
+		`<link rel="canonical" href="http://blog.example.test/p/public-post">`,
+		"application/ld+json",
+	} {
+		if !strings.Contains(resp.Body, want) {
+			t.Fatalf("public page is missing %q:\n%s", want, resp.Body)
+		}
+	}
+
+	e.request(http.MethodGet, "/p/"+draft.Slug, "", nil).expect(t, http.StatusNotFound)
+	e.request(http.MethodGet, "/p/missing", "", nil).expect(t, http.StatusNotFound)
+
+	// 改名后旧地址301跳转
+	e.api(http.MethodPut, "/api/articles/"+article.ID, map[string]interface{}{"slug": "new-post"}).expect(t, http.StatusOK)
+	resp = e.request(http.MethodGet, "/p/public-post", "", nil).expect(t, http.StatusMovedPermanently)
+	if resp.Header.Get("Location") != "/p/new-post" {
+		t.Fatalf("unexpected redirect target %q", resp.Header.Get("Location"))
+	}
+	e.runJobs()
+	if html, err := os.ReadFile(e.staticFile("public-post")); err != nil || !strings.Contains(string(html), "/p/new-post") {
+		t.Fatalf("old static page should be replaced with a redirect stub: %v", err)
+	}
+
+	// 从未公开的草稿改名不产生跳转
+	e.api(http.MethodPut, "/api/articles/"+draft.ID, map[string]interface{}{"slug": "hidden-renamed"}).expect(t, http.StatusOK)
+	e.runJobs()
+	e.request(http.MethodGet, "/p/"+draft.Slug, "", nil).expect(t, http.StatusNotFound)
+	var redirects int64
+	e.db.Model(&models.Redirect{}).Where("source_path = ?", "/p/"+draft.Slug).Count(&redirects)
+	if redirects != 0 || fileExists(e.staticFile(draft.Slug)) {
+		t.Fatal("renaming a draft should not create a redirect")
+	}
+
+	// 密码保护
+	e.createArticle(map[string]interface{}{
+		"title": "Secret", "content": "<p>classified</p>", "slug": "secret", "status": "published",
+		"visibility": "password", "password": "open-sesame",
+	})
+	resp = e.request(http.MethodGet, "/p/secret", "", nil).expect(t, http.StatusUnauthorized)
+	if strings.Contains(resp.Body, "classified") {
+		t.Fatal("password protected content leaked")
+	}
+}
+
+func TestArticleEvents(t *testing.T) {
+	e := newTestEnv(t)
+
+	var received []string
+	e.app.Events.Subscribe(events.All, func(event events.Event) {
+		received = append(received, event.Name)
+	})
+
+	article := e.createArticle(map[string]interface{}{"title": "Events", "content": "<p>x</p>"})
+	e.api(http.MethodPut, "/api/articles/"+article.ID, map[string]interface{}{"status": "published"}).expect(t, http.StatusOK)
+	e.api(http.MethodDelete, "/api/articles/"+article.ID, nil).expect(t, http.StatusOK)
+
+	want := []string{events.ArticleCreated, events.ArticleUpdated, events.ArticlePublished, events.ArticleDeleted}
+	if strings.Join(received, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected events %v, got %v", want, received)
+	}
+}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"net/http"
+	"static-hosting-server/internal/auth"
+	"static-hosting-server/internal/models"
+	"static-hosting-server/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// 创建或更新文章模板的请求
+type blueprintRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Title       string `json:"title"`   // 可包含 {{name}} 占位符
+	Content     string `json:"content"` // {{name}} 插入转义后的文本，{{{name}}} 插入原始HTML
+	Theme       string `json:"theme"`
+	SiteID      *uint  `json:"site_id"` // 仅不绑定站点的密钥可指定，创建时使用
+}
+
+func (req *blueprintRequest) input() services.BlueprintInput {
+	return services.BlueprintInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Title:       req.Title,
+		Content:     req.Content,
+		Theme:       req.Theme,
+	}
+}
+
+// 创建文章模板
+func (h *Handler) CreateBlueprint(c *gin.Context) {
+	var req blueprintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondFailure(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var siteID uint
+	if scope := auth.SiteScope(c); scope != nil {
+		siteID = *scope
+	} else if req.SiteID != nil {
+		siteID = *req.SiteID
+	}
+
+	blueprint, err := h.blueprintService.CreateBlueprint(siteID, req.input())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, N8nResponse{
+		Success: true,
+		Data:    blueprint,
+	})
+}
+
+// 获取文章模板列表
+func (h *Handler) ListBlueprints(c *gin.Context) {
+	blueprints, err := h.blueprintService.ListBlueprints(auth.SiteScope(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+		Data:    blueprints,
+	})
+}
+
+// 获取文章模板
+func (h *Handler) GetBlueprint(c *gin.Context) {
+	blueprint, ok := h.findBlueprint(c, false)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+		Data:    blueprint,
+	})
+}
+
+// 更新文章模板
+func (h *Handler) UpdateBlueprint(c *gin.Context) {
+	blueprint, ok := h.findBlueprint(c, true)
+	if !ok {
+		return
+	}
+
+	var req blueprintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondFailure(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updated, err := h.blueprintService.UpdateBlueprint(blueprint.ID, req.input())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+		Data:    updated,
+	})
+}
+
+// 删除文章模板
+func (h *Handler) DeleteBlueprint(c *gin.Context) {
+	blueprint, ok := h.findBlueprint(c, true)
+	if !ok {
+		return
+	}
+
+	if err := h.blueprintService.DeleteBlueprint(blueprint.ID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+	})
+}
+
+// 用变量渲染文章模板，返回生成的标题和内容，不创建文章
+func (h *Handler) RenderBlueprint(c *gin.Context) {
+	blueprint, ok := h.findBlueprint(c, false)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Variables map[string]string `json:"variables"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondFailure(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	title, content, err := h.blueprintService.Render(blueprint, req.Variables)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+		Data: gin.H{
+			"title":   title,
+			"content": content,
+		},
+	})
+}
+
+// 按路径中的ID查找当前密钥可访问的文章模板，默认站点的模板所有站点可读，修改时只能操作本站点的模板
+func (h *Handler) findBlueprint(c *gin.Context, write bool) (*models.Blueprint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondFailure(c, http.StatusBadRequest, "Invalid blueprint ID")
+		return nil, false
+	}
+
+	blueprint, err := h.blueprintService.GetBlueprintByID(uint(id))
+	if err == nil {
+		if scope := auth.SiteScope(c); scope != nil {
+			if (write && blueprint.SiteID != *scope) || !h.blueprintService.Usable(blueprint, *scope) {
+				err = gorm.ErrRecordNotFound
+			}
+		}
+	}
+	if err != nil {
+		respondFailure(c, http.StatusNotFound, "Blueprint not found")
+		return nil, false
+	}
+	return blueprint, true
+}
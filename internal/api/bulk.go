@@ -0,0 +1,105 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"static-hosting-server/internal/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 批量操作中单篇文章的结果
+type bulkItem struct {
+	ID      string            `json:"id"`
+	Success bool              `json:"success"`
+	Status  string            `json:"status,omitempty"`  // 处理后的文章状态
+	Version int               `json:"version,omitempty"` // 处理后的文章版本
+	Code    string            `json:"code,omitempty"`
+	Error   string            `json:"error,omitempty"`
+	Errors  map[string]string `json:"errors,omitempty"`
+}
+
+// 批量发布、下线、归档、延长过期时间或删除文章。逐篇处理时总是返回200，
+// 由 data.results 报告每篇文章的结果；原子操作中任何一篇失败时全部回滚，按失败原因返回错误
+func (h *Handler) BulkArticles(c *gin.Context) {
+	var req struct {
+		Action string   `json:"action"`
+		IDs    []string `json:"ids"`
+		Filter *struct {
+			Status        string     `json:"status"`
+			ExpiresBefore *time.Time `json:"expires_before"`
+		} `json:"filter"`
+		ExtendHours int  `json:"extend_hours"` // 仅 extend 使用
+		Atomic      bool `json:"atomic"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondFailure(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	input := services.BulkInput{
+		Action:   req.Action,
+		IDs:      req.IDs,
+		ExtendBy: time.Duration(req.ExtendHours) * time.Hour,
+		Atomic:   req.Atomic,
+	}
+	if req.Filter != nil {
+		input.Filter = &services.BulkFilter{
+			Status:        req.Filter.Status,
+			ExpiresBefore: req.Filter.ExpiresBefore,
+		}
+	}
+
+	results, err := h.articles(c).BulkArticles(input)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	items := make([]bulkItem, 0, len(results))
+	succeeded := 0
+	failure := -1 // 原子操作中失败的文章
+	status := http.StatusOK
+	for _, result := range results {
+		item := bulkItem{ID: result.ID, Success: result.Err == nil}
+		switch {
+		case result.Err == nil:
+			succeeded++
+			if result.Article != nil {
+				item.Status = result.Article.Status
+				item.Version = result.Article.Version
+			}
+		case errors.Is(result.Err, services.ErrBulkAborted):
+			item.Code = CodeAborted
+			item.Error = result.Err.Error()
+		default:
+			var itemStatus int
+			itemStatus, item.Error, item.Errors = classifyError(c, result.Err)
+			item.Code = statusCodes[itemStatus]
+			if req.Atomic {
+				status = itemStatus
+				failure = len(items)
+			}
+		}
+		items = append(items, item)
+	}
+
+	response := N8nResponse{
+		Success: status == http.StatusOK,
+		Data: gin.H{
+			"action":    req.Action,
+			"atomic":    req.Atomic,
+			"total":     len(results),
+			"succeeded": succeeded,
+			"failed":    len(results) - succeeded,
+			"results":   items,
+		},
+	}
+	if failure >= 0 {
+		response.Code = items[failure].Code
+		response.Error = "Article " + items[failure].ID + " failed, no changes were applied: " + items[failure].Error
+	}
+	c.JSON(status, response)
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bulkReport struct {
+	Total     int        `json:"total"`
+	Succeeded int        `json:"succeeded"`
+	Failed    int        `json:"failed"`
+	Results   []bulkItem `json:"results"`
+}
+
+func TestBulkArticles(t *testing.T) {
+	e := newTestEnv(t)
+	first := e.createArticle(map[string]interface{}{"title": "First", "content": "<p>1</p>", "slug": "first"})
+	second := e.createArticle(map[string]interface{}{"title": "Second", "content": "<p>2</p>", "slug": "second"})
+
+	// 逐篇处理：不存在的文章单独报告，其他文章照常发布
+	var report bulkReport
+	e.api(http.MethodPost, "/api/articles/bulk", map[string]interface{}{
+		"action": "publish", "ids": []string{first.ID, "missing", second.ID, first.ID},
+	}).expect(t, http.StatusOK).decode(t, &report)
+	if report.Total != 3 || report.Succeeded != 2 || report.Failed != 1 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if item := report.Results[1]; item.ID != "missing" || item.Success || item.Code != CodeNotFound {
+		t.Fatalf("missing article should be reported as not found: %+v", item)
+	}
+	if item := report.Results[0]; !item.Success || item.Status != "published" || item.Version != 2 {
+		t.Fatalf("unexpected result %+v", item)
+	}
+	e.runJobs()
+	if !fileExists(e.staticFile("second")) {
+		t.Fatal("bulk published article should be rendered")
+	}
+
+	// 原子操作：任何一篇失败时全部回滚
+	draft := e.createArticle(map[string]interface{}{"title": "Draft", "content": "<p>3</p>", "slug": "draft"})
+	atomic := e.api(http.MethodPost, "/api/articles/bulk", map[string]interface{}{
+		"action": "archive", "ids": []string{draft.ID, first.ID, "missing"}, "atomic": true,
+	}).expect(t, http.StatusNotFound)
+	atomic.decode(t, &report)
+	if atomic.N8nResponse.Code != CodeNotFound || report.Results[0].Code != CodeAborted || report.Results[1].Code != CodeAborted {
+		t.Fatalf("unexpected atomic failure: %s", atomic.Body)
+	}
+	for _, id := range []string{draft.ID, first.ID} {
+		if article, _ := e.app.Articles.GetArticleByID(id); article.Status == "archived" {
+			t.Fatalf("article %s should be rolled back", id)
+		}
+	}
+
+	// 按条件选择：延长即将过期的已发布文章
+	expiresAt := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	e.api(http.MethodPut, "/api/articles/"+first.ID, map[string]interface{}{"expires_at": expiresAt}).expect(t, http.StatusOK)
+	e.api(http.MethodPost, "/api/articles/bulk", map[string]interface{}{
+		"action": "extend", "extend_hours": 24, "atomic": true,
+		"filter": map[string]interface{}{"status": "published", "expires_before": time.Now().Add(24 * time.Hour)},
+	}).expect(t, http.StatusOK).decode(t, &report)
+	if report.Total != 1 || report.Results[0].ID != first.ID {
+		t.Fatalf("filter should only match the expiring article: %+v", report)
+	}
+	if article, _ := e.app.Articles.GetArticleByID(first.ID); !article.ExpiresAt.Equal(expiresAt.Add(24 * time.Hour)) {
+		t.Fatalf("expiry should be extended by 24h, got %v", article.ExpiresAt)
+	}
+
+	e.api(http.MethodPost, "/api/articles/bulk", map[string]interface{}{
+		"action": "delete", "filter": map[string]interface{}{"status": "draft"},
+	}).expect(t, http.StatusOK).decode(t, &report)
+	if report.Succeeded != 1 {
+		t.Fatalf("expected one draft deleted, got %+v", report)
+	}
+	e.api(http.MethodGet, "/api/articles/"+draft.ID, nil).expect(t, http.StatusNotFound)
+
+	// 请求本身不合法
+	invalid := e.api(http.MethodPost, "/api/articles/bulk", map[string]interface{}{"action": "explode"}).
+		expect(t, http.StatusUnprocessableEntity)
+	if invalid.Errors["action"] == "" || invalid.Errors["ids"] == "" {
+		t.Fatalf("expected action and ids errors, got %v", invalid.Errors)
+	}
+	e.api(http.MethodPost, "/api/articles/bulk", map[string]interface{}{"action": "extend", "ids": []string{first.ID}}).
+		expect(t, http.StatusUnprocessableEntity)
+}
+
+func TestBulkArchive(t *testing.T) {
+	e := newTestEnv(t)
+	created := e.createArticle(map[string]interface{}{"title": "Old news", "content": "<p>1</p>", "slug": "old-news", "status": "published"})
+
+	// 默认过期策略（unpublish）的文章归档后同样保留页面并显示归档提示
+	var report bulkReport
+	e.api(http.MethodPost, "/api/articles/bulk", map[string]interface{}{"action": "archive", "ids": []string{created.ID}}).
+		expect(t, http.StatusOK).decode(t, &report)
+	if report.Succeeded != 1 {
+		t.Fatalf("article should be archived: %+v", report)
+	}
+	e.runJobs()
+
+	live := e.request(http.MethodGet, "/p/old-news", "", nil).expect(t, http.StatusOK)
+	if !strings.Contains(live.Body, "已归档") {
+		t.Fatal("archived article should be served with the archive banner")
+	}
+	static, err := os.ReadFile(e.staticFile("old-news"))
+	if err != nil || !strings.Contains(string(static), "已归档") {
+		t.Fatalf("static page should show the archive banner: %v", err)
+	}
+}
+
+func TestBulkArticlesAdmin(t *testing.T) {
+	e := newTestEnv(t)
+	first := e.createArticle(map[string]interface{}{"title": "First", "content": "<p>1</p>", "slug": "first"})
+	second := e.createArticle(map[string]interface{}{"title": "Second", "content": "<p>2</p>", "slug": "second"})
+
+	list := e.admin(http.MethodGet, "/admin/articles", nil).expect(t, http.StatusOK)
+	if !strings.Contains(list.Body, `form="bulk-form"`) {
+		t.Fatal("article list should offer multi-select")
+	}
+
+	resp := e.admin(http.MethodPost, "/admin/articles/bulk", url.Values{
+		"action": {"publish"}, "ids": {first.ID, second.ID},
+	}).expect(t, http.StatusFound)
+	location := resp.Header.Get("Location")
+	if !strings.Contains(location, "succeeded=2") || !strings.Contains(location, "failed=0") {
+		t.Fatalf("unexpected redirect %q", location)
+	}
+	for _, id := range []string{first.ID, second.ID} {
+		if article, _ := e.app.Articles.GetArticleByID(id); article.Status != "published" {
+			t.Fatalf("article %s should be published", id)
+		}
+	}
+
+	summary := e.admin(http.MethodGet, location, nil).expect(t, http.StatusOK)
+	if !strings.Contains(summary.Body, "批量发布完成：成功 2 篇") {
+		t.Fatal("article list should show the bulk result")
+	}
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestEditorPreviewAutosaveAndConflict(t *testing.T) {
+	e := newTestEnv(t)
+	created := e.createArticle(map[string]interface{}{
+		"title": "Editor", "content": "<p>Original</p>", "slug": "editor", "status": "published",
+	})
+	e.runJobs()
+
+	// 预览使用文章主题渲染编辑中的内容，但不保存
+	preview := e.admin(http.MethodPost, "/admin/articles/preview", url.Values{
+		"id": {created.ID}, "content": {"<p>Edited in editor</p>"},
+	}).expect(t, http.StatusOK)
+	if !strings.Contains(preview.Body, "Edited in editor") || !strings.Contains(preview.Body, "Editor") {
+		t.Fatalf("preview should render edited content with the article title: %s", preview.Body)
+	}
+	article, err := e.app.Articles.GetArticleByID(created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Content != "<p>Original</p>" {
+		t.Fatalf("preview must not modify the article, got %q", article.Content)
+	}
+	e.admin(http.MethodPost, "/admin/articles/preview", url.Values{
+		"content": {"<p>New article</p>"}, "theme": {"missing-theme"},
+	}).expect(t, http.StatusBadRequest)
+
+	// 自动保存草稿，编辑页面提示恢复
+	base := strconv.Itoa(article.Version)
+	autosave := func() bool {
+		var result struct {
+			Conflict bool `json:"conflict"`
+		}
+		e.admin(http.MethodPost, "/admin/drafts", url.Values{
+			"article_id": {created.ID}, "version": {base}, "title": {"Editor"}, "content": {"<p>Draft</p>"},
+		}).expect(t, http.StatusOK).decode(t, &result)
+		return result.Conflict
+	}
+	if autosave() {
+		t.Fatal("unexpected conflict before the article changed")
+	}
+	page := e.admin(http.MethodGet, "/admin/articles/"+created.ID+"/edit", nil).expect(t, http.StatusOK)
+	if !strings.Contains(page.Body, "自动保存的未提交内容") {
+		t.Fatal("edit page should offer to restore the autosaved draft")
+	}
+
+	// 其他人（如n8n）修改文章后，自动保存和提交表单都会发现冲突
+	e.api(http.MethodPut, "/api/articles/"+created.ID, map[string]interface{}{"content": "<p>From n8n</p>"}).expect(t, http.StatusOK)
+	if !autosave() {
+		t.Fatal("autosave should report the concurrent update")
+	}
+
+	form := url.Values{"version": {base}, "title": {"Editor"}, "content": {"<p>From admin</p>"}}
+	conflict := e.admin(http.MethodPost, "/admin/articles/"+created.ID, form).expect(t, http.StatusBadRequest)
+	if !strings.Contains(conflict.Body, "修改冲突") || !strings.Contains(conflict.Body, "From admin") ||
+		!strings.Contains(conflict.Body, "From n8n") {
+		t.Fatalf("conflict page should keep the submitted content and show the latest version: %s", conflict.Body)
+	}
+	if article, _ = e.app.Articles.GetArticleByID(created.ID); article.Content != "<p>From n8n</p>" {
+		t.Fatalf("conflicting submit must not overwrite, got %q", article.Content)
+	}
+
+	// 基于最新版本提交后保存成功并删除草稿
+	form.Set("version", strconv.Itoa(article.Version))
+	e.admin(http.MethodPost, "/admin/articles/"+created.ID, form).expect(t, http.StatusFound)
+	if article, _ = e.app.Articles.GetArticleByID(created.ID); article.Content != "<p>From admin</p>" {
+		t.Fatalf("expected admin content, got %q", article.Content)
+	}
+	if _, err := e.app.Drafts.GetDraft(0, 0, created.ID); err == nil {
+		t.Fatal("draft should be removed after saving")
+	}
+}
+
+func TestEditorImageUpload(t *testing.T) {
+	e := newTestEnv(t)
+
+	upload := func(name string, content []byte) *testResponse {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, err := writer.CreateFormFile("file", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write(content)
+		writer.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/uploads", &body)
+		req.Host = e.cfg.Server.Domain
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return e.serveAdmin(req)
+	}
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+	var result struct {
+		URL string `json:"url"`
+	}
+	upload("pasted.png", png).expect(t, http.StatusCreated).decode(t, &result)
+	if !strings.HasPrefix(result.URL, "/uploads/") || !strings.HasSuffix(result.URL, ".png") {
+		t.Fatalf("unexpected upload url %q", result.URL)
+	}
+
+	served := e.request(http.MethodGet, result.URL, "", nil).expect(t, http.StatusOK)
+	if served.Body != string(png) {
+		t.Fatal("uploaded file should be served unchanged")
+	}
+
+	// 按内容识别类型，扩展名不可信
+	upload("script.png", []byte("<svg onload=alert(1)></svg>")).expect(t, http.StatusBadRequest)
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"static-hosting-server/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// 错误响应中的 code，客户端按 code 而不是 error 的文字判断错误类型
+const (
+	CodeInvalidRequest     = "invalid_request"     // 400 请求格式错误，如JSON无法解析、ID或查询参数不合法
+	CodeUnauthorized       = "unauthorized"        // 401 缺少或无效的API密钥
+	CodeForbidden          = "forbidden"           // 403 API密钥无权访问
+	CodeNotFound           = "not_found"           // 404 资源不存在
+	CodeConflict           = "conflict"            // 409 与已有数据冲突，如slug重复
+	CodePreconditionFailed = "precondition_failed" // 412 If-Match 与文章版本不一致
+	CodeValidationFailed   = "validation_failed"   // 422 字段校验失败，详见 errors
+	CodeInternal           = "internal_error"      // 500 服务器内部错误
+
+	// 仅用于批量操作的单篇结果：原子批量操作中其他文章失败，本文章未修改
+	CodeAborted = "aborted"
+)
+
+// 各状态码对应的错误码
+var statusCodes = map[int]string{
+	http.StatusBadRequest:          CodeInvalidRequest,
+	http.StatusUnauthorized:        CodeUnauthorized,
+	http.StatusForbidden:           CodeForbidden,
+	http.StatusNotFound:            CodeNotFound,
+	http.StatusConflict:            CodeConflict,
+	http.StatusPreconditionFailed:  CodePreconditionFailed,
+	http.StatusUnprocessableEntity: CodeValidationFailed,
+	http.StatusInternalServerError: CodeInternal,
+}
+
+// 返回失败响应，错误码由状态码确定
+func respondFailure(c *gin.Context, status int, message string) {
+	c.JSON(status, N8nResponse{
+		Success: false,
+		Code:    statusCodes[status],
+		Error:   message,
+	})
+}
+
+// 按服务返回的错误类型选择状态码；无法识别的错误只记录日志，不把内部信息返回给客户端
+func respondError(c *gin.Context, err error) {
+	status, message, fieldErrors := classifyError(c, err)
+	if fieldErrors != nil {
+		respondFieldErrors(c, fieldErrors)
+		return
+	}
+	respondFailure(c, status, message)
+}
+
+// 服务错误对应的状态码、返回给客户端的信息和字段错误
+func classifyError(c *gin.Context, err error) (int, string, services.FieldErrors) {
+	var invalid services.FieldErrors
+	switch {
+	case errors.As(err, &invalid):
+		return http.StatusUnprocessableEntity, "validation failed", invalid
+	case errors.Is(err, services.ErrNotFound):
+		return http.StatusNotFound, err.Error(), nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound, "Resource not found", nil
+	case errors.Is(err, services.ErrConflict):
+		return http.StatusConflict, err.Error(), nil
+	case errors.Is(err, services.ErrEditConflict):
+		return http.StatusPreconditionFailed, err.Error(), nil
+	default:
+		log.Printf("Request %s %s failed: %v", c.Request.Method, c.Request.URL.Path, err)
+		return http.StatusInternalServerError, "Internal server error", nil
+	}
+}
+
+// 返回422和各字段的错误
+func respondFieldErrors(c *gin.Context, fieldErrors services.FieldErrors) {
+	c.JSON(http.StatusUnprocessableEntity, N8nResponse{
+		Success: false,
+		Code:    CodeValidationFailed,
+		Error:   "validation failed",
+		Errors:  fieldErrors,
+	})
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorCodes(t *testing.T) {
+	e := newTestEnv(t)
+	created := e.createArticle(map[string]interface{}{"title": "Taken", "content": "<p>a</p>", "slug": "taken"})
+
+	// 所有不合法的字段一起返回
+	invalid := e.api(http.MethodPost, "/api/articles", map[string]interface{}{
+		"title":      strings.Repeat("长", 256),
+		"content":    "<p>b</p>",
+		"slug":       "Not A Slug",
+		"status":     "deleted",
+		"expires_at": time.Now().Add(-time.Hour),
+	}).expect(t, http.StatusUnprocessableEntity)
+	if invalid.N8nResponse.Code != CodeValidationFailed {
+		t.Fatalf("unexpected code %q", invalid.N8nResponse.Code)
+	}
+	for _, field := range []string{"title", "slug", "status", "expires_at"} {
+		if invalid.Errors[field] == "" {
+			t.Errorf("expected an error for %s, got %v", field, invalid.Errors)
+		}
+	}
+
+	conflict := e.api(http.MethodPost, "/api/articles", map[string]interface{}{
+		"title": "Again", "content": "<p>c</p>", "slug": "taken",
+	}).expect(t, http.StatusConflict)
+	if conflict.N8nResponse.Code != CodeConflict {
+		t.Fatalf("unexpected code %q", conflict.N8nResponse.Code)
+	}
+	second := e.createArticle(map[string]interface{}{"title": "Second", "content": "<p>d</p>", "slug": "second"})
+	e.api(http.MethodPut, "/api/articles/"+second.ID, map[string]interface{}{"slug": "taken"}).
+		expect(t, http.StatusConflict)
+
+	missing := e.api(http.MethodPut, "/api/articles/missing", map[string]interface{}{"title": "x"}).
+		expect(t, http.StatusNotFound)
+	if missing.N8nResponse.Code != CodeNotFound {
+		t.Fatalf("unexpected code %q", missing.N8nResponse.Code)
+	}
+	e.api(http.MethodDelete, "/api/articles/missing", nil).expect(t, http.StatusNotFound)
+
+	// 已过期文章原样提交过期时间不报错，修改为其他过去的时间报错
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	e.db.Table("articles").Where("id = ?", created.ID).Update("expires_at", past)
+	e.api(http.MethodPut, "/api/articles/"+created.ID, map[string]interface{}{"title": "Kept", "expires_at": past}).
+		expect(t, http.StatusOK)
+	e.api(http.MethodPut, "/api/articles/"+created.ID, map[string]interface{}{"expires_at": past.Add(-time.Hour)}).
+		expect(t, http.StatusUnprocessableEntity)
+
+	// 请求体无法解析
+	req := httptest.NewRequest(http.MethodPost, "/api/articles", strings.NewReader("{"))
+	req.Host = e.cfg.Server.Domain
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"code":"invalid_request"`) {
+		t.Fatalf("expected invalid_request, got %d: %s", w.Code, w.Body.String())
+	}
+
+	unauthorized := e.request(http.MethodGet, "/api/articles", "", nil).expect(t, http.StatusUnauthorized)
+	if unauthorized.N8nResponse.Code != CodeUnauthorized {
+		t.Fatalf("unexpected code %q", unauthorized.N8nResponse.Code)
+	}
+}
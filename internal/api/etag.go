@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"static-hosting-server/internal/models"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 文章的实体标签，由版本号生成，文章每次修改后改变
+func articleETag(article *models.Article) string {
+	return `"` + strconv.Itoa(article.Version) + `"`
+}
+
+// 在响应头中返回文章的实体标签
+func setArticleETag(c *gin.Context, article *models.Article) {
+	c.Header("ETag", articleETag(article))
+}
+
+// 解析 If-Match 请求头中要求的文章版本。未提供或为 * 时返回nil表示不检查。
+// 列出多个实体标签时任何一个与文章的当前版本相同即可，此时返回当前版本，由服务在修改时再次检查；
+// 都不匹配或都无法识别时返回 ok=false。W/ 前缀会被忽略，以兼容会弱化ETag的代理
+func (h *Handler) ifMatchVersion(c *gin.Context, id string) (version *int, ok bool) {
+	var versions []int
+	present := false
+	for _, value := range c.Request.Header.Values("If-Match") {
+		for _, tag := range strings.Split(value, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "" {
+				continue
+			}
+			if tag == "*" {
+				return nil, true
+			}
+			present = true
+			if parsed, valid := parseVersionTag(tag); valid {
+				versions = append(versions, parsed)
+			}
+		}
+	}
+
+	switch {
+	case !present:
+		return nil, true
+	case len(versions) == 0:
+		return nil, false
+	case len(versions) == 1:
+		return &versions[0], true
+	}
+
+	article, err := h.articles(c).GetArticleByID(id)
+	if err != nil {
+		// 文章不存在时由修改操作返回404
+		return &versions[0], true
+	}
+	for _, v := range versions {
+		if v == article.Version {
+			return &article.Version, true
+		}
+	}
+	return nil, false
+}
+
+// 解析单个实体标签中的版本号，无法识别的标签不可能匹配
+func parseVersionTag(tag string) (int, bool) {
+	tag = strings.TrimPrefix(tag, "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+	parsed, err := strconv.Atoi(tag[1 : len(tag)-1])
+	if err != nil || parsed <= 0 {
+		return 0, false
+	}
+	return parsed, true
+}
+
+// 文章已被修改时返回412，data 中为文章的当前内容，客户端可以据此合并后重新提交
+func (h *Handler) respondPreconditionFailed(c *gin.Context, id string) {
+	response := N8nResponse{
+		Success: false,
+		Code:    CodePreconditionFailed,
+		Error:   "Article has been modified, fetch the latest version and retry",
+	}
+	if article, err := h.articles(c).GetArticleByID(id); err == nil {
+		setArticleETag(c, article)
+		response.Data = article
+	}
+	c.JSON(http.StatusPreconditionFailed, response)
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"static-hosting-server/internal/models"
+)
+
+func TestArticleETagAndIfMatch(t *testing.T) {
+	e := newTestEnv(t)
+	article := e.createArticle(map[string]interface{}{
+		"title": "Versioned", "content": "<p>v1</p>", "slug": "versioned", "status": "published",
+	})
+	if article.Version != 1 {
+		t.Fatalf("new article should start at version 1, got %d", article.Version)
+	}
+
+	get := e.api(http.MethodGet, "/api/articles/"+article.ID, nil).expect(t, http.StatusOK)
+	etag := get.Header.Get("ETag")
+	if etag != `"1"` {
+		t.Fatalf("unexpected ETag %q", etag)
+	}
+
+	ifMatch := func(tag string) http.Header {
+		return http.Header{"If-Match": {tag}}
+	}
+
+	// 第一个客户端按读取的版本更新成功，版本号加1
+	var updated models.Article
+	resp := e.requestWithHeader(http.MethodPut, "/api/articles/"+article.ID, testAPIKey, ifMatch(etag),
+		map[string]interface{}{"content": "<p>v2 from n8n</p>"}).expect(t, http.StatusOK)
+	resp.decode(t, &updated)
+	if updated.Version != 2 || resp.Header.Get("ETag") != `"2"` {
+		t.Fatalf("expected version 2, got %d (ETag %q)", updated.Version, resp.Header.Get("ETag"))
+	}
+
+	// 第二个客户端仍使用旧版本，返回412和当前内容，不覆盖
+	var current models.Article
+	stale := e.requestWithHeader(http.MethodPut, "/api/articles/"+article.ID, testAPIKey, ifMatch(etag),
+		map[string]interface{}{"content": "<p>v2 from editor</p>"}).expect(t, http.StatusPreconditionFailed)
+	stale.decode(t, &current)
+	if current.Content != "<p>v2 from n8n</p>" || stale.Header.Get("ETag") != `"2"` {
+		t.Fatalf("412 should return the current article, got %q (ETag %q)", current.Content, stale.Header.Get("ETag"))
+	}
+
+	// 无法识别的ETag不会匹配；弱ETag和 * 可以使用
+	e.requestWithHeader(http.MethodPut, "/api/articles/"+article.ID, testAPIKey, ifMatch("garbage"),
+		map[string]interface{}{"title": "x"}).expect(t, http.StatusPreconditionFailed)
+	e.requestWithHeader(http.MethodPut, "/api/articles/"+article.ID, testAPIKey, ifMatch(`W/"2"`),
+		map[string]interface{}{"title": "Weak"}).expect(t, http.StatusOK)
+	e.requestWithHeader(http.MethodPut, "/api/articles/"+article.ID, testAPIKey, ifMatch("*"),
+		map[string]interface{}{"title": "Any"}).expect(t, http.StatusOK)
+	e.requestWithHeader(http.MethodPatch, "/api/articles/"+article.ID, testAPIKey, ifMatch("*"),
+		map[string]interface{}{"title": "Any patch"}).expect(t, http.StatusOK)
+
+	// 列出多个实体标签时任何一个匹配即可
+	current = models.Article{}
+	e.api(http.MethodGet, "/api/articles/"+article.ID, nil).expect(t, http.StatusOK).decode(t, &current)
+	e.requestWithHeader(http.MethodPut, "/api/articles/"+article.ID, testAPIKey, ifMatch(`"1", garbage, `+articleETag(&current)),
+		map[string]interface{}{"title": "Listed"}).expect(t, http.StatusOK)
+	e.requestWithHeader(http.MethodPut, "/api/articles/"+article.ID, testAPIKey, ifMatch(`"1", `+articleETag(&current)),
+		map[string]interface{}{"title": "Stale list"}).expect(t, http.StatusPreconditionFailed)
+	e.requestWithHeader(http.MethodPut, "/api/articles/"+article.ID, testAPIKey,
+		http.Header{"If-Match": {`"1"`, `"` + strconv.Itoa(current.Version+1) + `"`}},
+		map[string]interface{}{"title": "Repeated header"}).expect(t, http.StatusOK)
+	e.requestWithHeader(http.MethodPut, "/api/articles/missing", testAPIKey, ifMatch(`"1", "2"`),
+		map[string]interface{}{"title": "x"}).expect(t, http.StatusNotFound)
+
+	// 不带 If-Match 时保持原来的行为
+	e.api(http.MethodPut, "/api/articles/"+article.ID, map[string]interface{}{"title": "Blind"}).expect(t, http.StatusOK)
+
+	// 删除同样检查版本
+	e.requestWithHeader(http.MethodDelete, "/api/articles/"+article.ID, testAPIKey, ifMatch(`"1"`), nil).
+		expect(t, http.StatusPreconditionFailed)
+	latest := e.api(http.MethodGet, "/api/articles/"+article.ID, nil).expect(t, http.StatusOK)
+	e.requestWithHeader(http.MethodDelete, "/api/articles/"+article.ID, testAPIKey, ifMatch(latest.Header.Get("ETag")), nil).
+		expect(t, http.StatusOK)
+	e.api(http.MethodGet, "/api/articles/"+article.ID, nil).expect(t, http.StatusNotFound)
+}
+
+func TestExpiryBumpsArticleVersion(t *testing.T) {
+	e := newTestEnv(t)
+	article := e.createArticle(map[string]interface{}{
+		"title": "Expiring", "content": "<p>x</p>", "slug": "expiring", "status": "published",
+	})
+
+	// 过期处理修改了文章状态，之前读取的版本不能再覆盖
+	if _, err := e.app.Articles.ExpireArticle(article.ID); err != nil {
+		t.Fatal(err)
+	}
+	e.requestWithHeader(http.MethodPut, "/api/articles/"+article.ID, testAPIKey, http.Header{"If-Match": {`"1"`}},
+		map[string]interface{}{"status": "published"}).expect(t, http.StatusPreconditionFailed)
+}
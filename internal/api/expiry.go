@@ -0,0 +1,127 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"static-hosting-server/internal/auth"
+	"static-hosting-server/internal/models"
+	"static-hosting-server/internal/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 未指定过期时间时文章的默认有效期：API密钥的设置优先，其次为站点和全局配置，0 表示不过期
+func (h *Handler) defaultTTL(c *gin.Context, siteID uint) time.Duration {
+	if apiKey := auth.APIKey(c); apiKey != nil && apiKey.DefaultTTLHours > 0 {
+		return time.Duration(apiKey.DefaultTTLHours) * time.Hour
+	}
+	return h.siteService.DefaultTTL(siteID)
+}
+
+// 延长文章的过期时间
+func (h *Handler) ExtendArticleExpiry(c *gin.Context) {
+	var req struct {
+		ExtendHours int `json:"extend_hours"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondFailure(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.changeExpiry(c, func(articles *services.ArticleService, id string, version *int) (*models.Article, error) {
+		return articles.ExtendExpiry(id, time.Duration(req.ExtendHours)*time.Hour, version)
+	})
+}
+
+// 取消文章的过期时间
+func (h *Handler) MakeArticlePermanent(c *gin.Context) {
+	h.changeExpiry(c, func(articles *services.ArticleService, id string, version *int) (*models.Article, error) {
+		return articles.MakePermanent(id, version)
+	})
+}
+
+// 恢复已过期的文章并重新生成页面。新的过期时间由 expires_at、extend_hours（从现在起）或 permanent 指定，
+// 都未指定时使用默认有效期
+func (h *Handler) ReviveArticle(c *gin.Context) {
+	var req struct {
+		ExpiresAt   *time.Time `json:"expires_at"`
+		ExtendHours int        `json:"extend_hours"`
+		Permanent   bool       `json:"permanent"`
+	}
+
+	// 请求体可以为空，使用默认有效期
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondFailure(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	options := 0
+	for _, set := range []bool{req.ExpiresAt != nil, req.ExtendHours != 0, req.Permanent} {
+		if set {
+			options++
+		}
+	}
+	if options > 1 {
+		respondFieldErrors(c, services.FieldErrors{"expires_at": "specify only one of expires_at, extend_hours and permanent"})
+		return
+	}
+	if req.ExtendHours < 0 {
+		respondFieldErrors(c, services.FieldErrors{"extend_hours": "extend_hours must be positive"})
+		return
+	}
+
+	h.changeExpiry(c, func(articles *services.ArticleService, id string, version *int) (*models.Article, error) {
+		expiresAt := req.ExpiresAt
+		if expiresAt == nil && !req.Permanent {
+			ttl := time.Duration(req.ExtendHours) * time.Hour
+			if ttl == 0 {
+				article, err := articles.GetArticleByID(id)
+				if err != nil {
+					return nil, err
+				}
+				ttl = h.defaultTTL(c, article.SiteID)
+			}
+			if ttl > 0 {
+				t := time.Now().Add(ttl)
+				expiresAt = &t
+			}
+		}
+		return articles.ReviveArticle(id, expiresAt, version)
+	})
+}
+
+// 修改文章过期时间的公共流程：检查 If-Match，执行修改并返回修改后的文章
+func (h *Handler) changeExpiry(c *gin.Context, change func(articles *services.ArticleService, id string, version *int) (*models.Article, error)) {
+	id := c.Param("id")
+
+	version, ok := h.ifMatchVersion(c, id)
+	if !ok {
+		h.respondPreconditionFailed(c, id)
+		return
+	}
+
+	article, err := change(h.articles(c), id, version)
+	if errors.Is(err, services.ErrEditConflict) {
+		h.respondPreconditionFailed(c, id)
+		return
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	publishURL := ""
+	if article.Status == "published" {
+		publishURL = h.articleService.PublicURL(article)
+	}
+
+	setArticleETag(c, article)
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+		Data:    article,
+		URL:     publishURL,
+	})
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"static-hosting-server/internal/models"
+)
+
+func TestArticleExpiryEndpoints(t *testing.T) {
+	e := newTestEnv(t)
+	expiresAt := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	created := e.createArticle(map[string]interface{}{
+		"title": "Expiring", "content": "<p>Body</p>", "slug": "expiring", "status": "published", "expires_at": expiresAt,
+	})
+	path := "/api/articles/" + created.ID
+
+	// 从原过期时间开始延长
+	var article models.Article
+	e.api(http.MethodPost, path+"/extend", map[string]interface{}{"extend_hours": 24}).
+		expect(t, http.StatusOK).decode(t, &article)
+	if !article.ExpiresAt.Equal(expiresAt.Add(24 * time.Hour)) {
+		t.Fatalf("expiry should be extended by 24h, got %v", article.ExpiresAt)
+	}
+	e.api(http.MethodPost, path+"/extend", map[string]interface{}{"extend_hours": 0}).expect(t, http.StatusUnprocessableEntity)
+	e.requestWithHeader(http.MethodPost, path+"/extend", testAPIKey, http.Header{"If-Match": {`"1"`}},
+		map[string]interface{}{"extend_hours": 1}).expect(t, http.StatusPreconditionFailed)
+
+	e.api(http.MethodPost, path+"/make-permanent", nil).expect(t, http.StatusOK).decode(t, &article)
+	if article.ExpiresAt != nil {
+		t.Fatalf("article should no longer expire, got %v", article.ExpiresAt)
+	}
+	e.api(http.MethodPost, path+"/extend", map[string]interface{}{"extend_hours": 1}).expect(t, http.StatusUnprocessableEntity)
+
+	// 已被过期处理的文章不能延长，只能恢复
+	e.api(http.MethodPost, path+"/revive", nil).expect(t, http.StatusConflict)
+	if _, err := e.app.Articles.ExpireArticle(created.ID); err != nil {
+		t.Fatalf("expire article: %v", err)
+	}
+	e.runJobs()
+	if fileExists(e.staticFile("expiring")) {
+		t.Fatal("expired article should be taken offline")
+	}
+	e.api(http.MethodPost, path+"/extend", map[string]interface{}{"extend_hours": 1}).expect(t, http.StatusConflict)
+	e.api(http.MethodPost, path+"/make-permanent", nil).expect(t, http.StatusConflict)
+	e.api(http.MethodPost, path+"/revive", map[string]interface{}{"extend_hours": 1, "permanent": true}).
+		expect(t, http.StatusUnprocessableEntity)
+
+	resp := e.api(http.MethodPost, path+"/revive", map[string]interface{}{"extend_hours": 48}).expect(t, http.StatusOK)
+	resp.decode(t, &article)
+	if article.Status != "published" || article.ExpiresAt == nil || time.Until(*article.ExpiresAt) < 47*time.Hour {
+		t.Fatalf("article should be republished for 48h, got status=%s expires_at=%v", article.Status, article.ExpiresAt)
+	}
+	if resp.URL == "" {
+		t.Fatal("revived article should return its public URL")
+	}
+	e.runJobs()
+	if !fileExists(e.staticFile("expiring")) {
+		t.Fatal("revived article should be rendered again")
+	}
+
+	e.api(http.MethodPost, "/api/articles/missing/revive", nil).expect(t, http.StatusNotFound)
+}
+
+func TestArticleDefaultTTL(t *testing.T) {
+	e := newTestEnv(t)
+	site, err := e.app.Sites.CreateSite("Campaigns", "", "", "")
+	if err != nil {
+		t.Fatalf("create site: %v", err)
+	}
+	key, err := e.app.Auth.GenerateAPIKey("short-lived", "", nil, &site.ID, 6)
+	if err != nil {
+		t.Fatalf("create key: %v", err)
+	}
+	e.cfg.Expiry.DefaultTTL = 30 * 24 * time.Hour
+
+	create := func(apiKey string, body map[string]interface{}) models.Article {
+		var article models.Article
+		e.request(http.MethodPost, "/api/articles", apiKey, body).expect(t, http.StatusCreated).decode(t, &article)
+		return article
+	}
+	expiresIn := func(article models.Article, want time.Duration) {
+		t.Helper()
+		if article.ExpiresAt == nil {
+			t.Fatalf("article %s should expire in %v", article.Slug, want)
+		}
+		if got := time.Until(*article.ExpiresAt); got > want || got < want-time.Minute {
+			t.Fatalf("article %s should expire in %v, got %v", article.Slug, want, got)
+		}
+	}
+
+	// 全局配置、站点设置、API密钥设置依次优先
+	expiresIn(create(testAPIKey, map[string]interface{}{"title": "Global", "content": "<p>1</p>"}), 30*24*time.Hour)
+
+	e.api(http.MethodPut, fmt.Sprintf("/api/sites/%d/default-ttl", site.ID), map[string]interface{}{"default_ttl_hours": 72}).
+		expect(t, http.StatusOK)
+	expiresIn(create(testAPIKey, map[string]interface{}{"title": "Site", "content": "<p>2</p>", "site_id": site.ID}), 72*time.Hour)
+	expiresIn(create(key.Key, map[string]interface{}{"title": "Key", "content": "<p>3</p>"}), 6*time.Hour)
+
+	// 指定过期时间或 permanent 时不使用默认有效期
+	explicit := time.Now().Add(time.Hour).Truncate(time.Second)
+	if article := create(key.Key, map[string]interface{}{"title": "Explicit", "content": "<p>4</p>", "expires_at": explicit}); !article.ExpiresAt.Equal(explicit) {
+		t.Fatalf("explicit expiry should be kept, got %v", article.ExpiresAt)
+	}
+	if article := create(key.Key, map[string]interface{}{"title": "Forever", "content": "<p>5</p>", "permanent": true}); article.ExpiresAt != nil {
+		t.Fatalf("permanent article should not expire, got %v", article.ExpiresAt)
+	}
+	e.api(http.MethodPost, "/api/articles", map[string]interface{}{
+		"title": "Both", "content": "<p>6</p>", "permanent": true, "expires_at": explicit,
+	}).expect(t, http.StatusUnprocessableEntity)
+
+	e.api(http.MethodPut, fmt.Sprintf("/api/sites/%d/default-ttl", site.ID), map[string]interface{}{"default_ttl_hours": -1}).
+		expect(t, http.StatusUnprocessableEntity)
+	e.api(http.MethodPost, "/api/keys", map[string]interface{}{"name": "bad", "default_ttl_hours": -1}).
+		expect(t, http.StatusUnprocessableEntity)
+}
+
+func TestReviveArticleAdmin(t *testing.T) {
+	e := newTestEnv(t)
+	created := e.createArticle(map[string]interface{}{"title": "Old", "content": "<p>1</p>", "slug": "old", "status": "published"})
+	if _, err := e.app.Articles.ExpireArticle(created.ID); err != nil {
+		t.Fatalf("expire article: %v", err)
+	}
+
+	list := e.admin(http.MethodGet, "/admin/articles", nil).expect(t, http.StatusOK)
+	if !strings.Contains(list.Body, "/admin/articles/"+created.ID+"/revive") {
+		t.Fatal("expired article should offer a revive action")
+	}
+
+	e.admin(http.MethodPost, "/admin/articles/"+created.ID+"/revive", url.Values{}).expect(t, http.StatusFound)
+	if article, _ := e.app.Articles.GetArticleByID(created.ID); article.Status != "published" || article.ExpiresAt != nil {
+		t.Fatalf("article should be republished without expiry, got %s %v", article.Status, article.ExpiresAt)
+	}
+}
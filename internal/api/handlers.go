@@ -1,10 +1,15 @@
 package api
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"static-hosting-server/internal/app"
 	"static-hosting-server/internal/auth"
 	"static-hosting-server/internal/config"
+	"static-hosting-server/internal/jobs"
 	"static-hosting-server/internal/services"
+	"static-hosting-server/internal/theme"
 	"strconv"
 	"time"
 
@@ -13,26 +18,37 @@ import (
 )
 
 type Handler struct {
-	db             *gorm.DB
-	cfg            *config.Config
-	authService    *auth.AuthService
-	articleService *services.ArticleService
+	db               *gorm.DB
+	cfg              *config.Config
+	authService      *auth.AuthService
+	articleService   *services.ArticleService
+	domainService    *services.DomainService
+	siteService      *services.SiteService
+	previewService   *services.PreviewService
+	redirectService  *services.RedirectService
+	blueprintService *services.BlueprintService
+	themeManager     *theme.Manager
+	jobQueue         *jobs.Queue
 }
 
-func NewHandler(db *gorm.DB, cfg *config.Config) *Handler {
-	authService := auth.NewAuthService(db, cfg)
-	articleService := services.NewArticleService(db, cfg)
-
+func NewHandler(app *app.App) *Handler {
 	return &Handler{
-		db:             db,
-		cfg:            cfg,
-		authService:    authService,
-		articleService: articleService,
+		db:               app.DB,
+		cfg:              app.Config,
+		authService:      app.Auth,
+		articleService:   app.Articles,
+		domainService:    app.Domains,
+		siteService:      app.Sites,
+		previewService:   app.Previews,
+		redirectService:  app.Redirects,
+		blueprintService: app.Blueprints,
+		themeManager:     app.Themes,
+		jobQueue:         app.Jobs,
 	}
 }
 
-func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
-	handler := NewHandler(db, cfg)
+func SetupRoutes(router *gin.Engine, app *app.App) {
+	handler := NewHandler(app)
 
 	// API 路由组
 	api := router.Group("/api")
@@ -42,10 +58,28 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 		articles := api.Group("/articles")
 		{
 			articles.POST("", handler.CreateArticle)
+			articles.POST("/bulk", handler.BulkArticles)
 			articles.GET("/:id", handler.GetArticle)
 			articles.PUT("/:id", handler.UpdateArticle)
+			articles.PATCH("/:id", handler.PatchArticle)
 			articles.DELETE("/:id", handler.DeleteArticle)
+			articles.POST("/:id/extend", handler.ExtendArticleExpiry)
+			articles.POST("/:id/make-permanent", handler.MakeArticlePermanent)
+			articles.POST("/:id/revive", handler.ReviveArticle)
 			articles.GET("", handler.ListArticles)
+
+			// 草稿预览链接
+			articles.POST("/:id/previews", handler.CreatePreview)
+			articles.GET("/:id/previews", handler.ListPreviews)
+		}
+		api.DELETE("/previews/:id", handler.RevokePreview)
+
+		// 回收站
+		trash := api.Group("/trash")
+		{
+			trash.GET("", handler.ListTrash)
+			trash.POST("/:id/restore", handler.RestoreArticle)
+			trash.DELETE("/:id", handler.PurgeArticle)
 		}
 
 		// API密钥管理
@@ -55,53 +89,218 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 			apiKeys.GET("", handler.ListAPIKeys)
 			apiKeys.DELETE("/:id", handler.DeleteAPIKey)
 		}
+
+		// 自定义域名管理（仅限不绑定站点的密钥）
+		domains := api.Group("/domains")
+		domains.Use(requireGlobalScope())
+		{
+			domains.POST("", handler.CreateDomain)
+			domains.GET("", handler.ListDomains)
+			domains.DELETE("/:id", handler.DeleteDomain)
+		}
+
+		// 站点管理（仅限不绑定站点的密钥）
+		sites := api.Group("/sites")
+		sites.Use(requireGlobalScope())
+		{
+			sites.POST("", handler.CreateSite)
+			sites.GET("", handler.ListSites)
+			sites.DELETE("/:id", handler.DeleteSite)
+			sites.PUT("/:id/theme", handler.SetSiteTheme)
+			sites.PUT("/:id/default-ttl", handler.SetSiteDefaultTTL)
+		}
+
+		// 跳转管理
+		redirects := api.Group("/redirects")
+		{
+			redirects.POST("", handler.CreateRedirect)
+			redirects.GET("", handler.ListRedirects)
+			redirects.DELETE("/:id", handler.DeleteRedirect)
+		}
+
+		// 文章模板
+		blueprints := api.Group("/blueprints")
+		{
+			blueprints.POST("", handler.CreateBlueprint)
+			blueprints.GET("", handler.ListBlueprints)
+			blueprints.GET("/:id", handler.GetBlueprint)
+			blueprints.PUT("/:id", handler.UpdateBlueprint)
+			blueprints.DELETE("/:id", handler.DeleteBlueprint)
+			blueprints.POST("/:id/render", handler.RenderBlueprint)
+		}
+
+		// 主题管理
+		themes := api.Group("/themes")
+		{
+			themes.GET("", handler.ListThemes)
+			themes.POST("/:name/validate", handler.ValidateTheme)
+		}
+
+		// 定时任务（仅限不绑定站点的密钥）
+		schedulerJobs := api.Group("/scheduler/jobs")
+		schedulerJobs.Use(requireGlobalScope())
+		{
+			schedulerJobs.GET("", handler.ListSchedulerJobs)
+			schedulerJobs.POST("/:name/run", handler.RunSchedulerJob)
+		}
 	}
 
+	// 接口文档，无需API密钥
+	router.GET("/api/openapi.json", handler.GetOpenAPISpec)
+	router.GET("/api/docs", handler.APIDocs)
+
 	// 公开的文章访问API
 	router.GET("/p/:slug", handler.GetPublishedArticle)
+	router.POST("/p/:slug/unlock", handler.UnlockArticle)
+	router.GET("/preview/:token", handler.GetPreview)
+	router.GET("/sitemap.xml", handler.GetSitemap)
+	// 未绑定域名的站点通过 /s/<存储前缀> 访问
+	router.GET("/s/:site/p/:slug", handler.GetPublishedArticle)
+	router.POST("/s/:site/p/:slug/unlock", handler.UnlockArticle)
+	router.GET("/s/:site/sitemap.xml", handler.GetSitemap)
+
+	// 后台编辑器上传的图片
+	if uploads := app.Config.Storage.UploadsPath; uploads != "" {
+		router.Static("/uploads", uploads)
+	}
+
+	// 其他未匹配的路径按跳转表处理
+	router.NoRoute(handler.ServeRedirect)
+}
+
+// 限制只有不绑定站点的API密钥才能访问
+func requireGlobalScope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if auth.SiteScope(c) != nil {
+			respondFailure(c, http.StatusForbidden, "API key is restricted to a single site")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// 当前请求可操作的文章服务，限定在API密钥所属站点内
+func (h *Handler) articles(c *gin.Context) *services.ArticleService {
+	return h.articleService.ForSite(auth.SiteScope(c))
+}
+
+// 文章的SEO和分享卡片字段，省略的字段不修改，传空字符串表示清除
+type articleMetaRequest struct {
+	Description  *string `json:"description"`
+	CanonicalURL *string `json:"canonical_url"`
+	CoverImage   *string `json:"cover_image"`
+	Author       *string `json:"author"`
+	OGTitle      *string `json:"og_title"`
+	TwitterCard  *string `json:"twitter_card"` // summary, summary_large_image
+}
+
+func (req *articleMetaRequest) meta() services.ArticleMetaInput {
+	return services.ArticleMetaInput{
+		Description:  req.Description,
+		CanonicalURL: req.CanonicalURL,
+		CoverImage:   req.CoverImage,
+		Author:       req.Author,
+		OGTitle:      req.OGTitle,
+		TwitterCard:  req.TwitterCard,
+	}
 }
 
 // n8n 兼容的响应格式
 type N8nResponse struct {
-	Success bool        `json:"success"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
-	URL     string      `json:"url,omitempty"`
+	Success bool              `json:"success"`
+	Data    interface{}       `json:"data,omitempty"`
+	Code    string            `json:"code,omitempty"` // 失败时的错误码，见 Code* 常量
+	Error   string            `json:"error,omitempty"`
+	Errors  map[string]string `json:"errors,omitempty"` // 字段校验错误，键为请求中的字段名
+	URL     string            `json:"url,omitempty"`
 }
 
 // 创建文章
 func (h *Handler) CreateArticle(c *gin.Context) {
 	var req struct {
-		Title     string     `json:"title" binding:"required"`
-		Content   string     `json:"content" binding:"required"`
+		Title     string     `json:"title"`   // 使用文章模板时可省略
+		Content   string     `json:"content"` // 使用文章模板时可省略
 		Slug      string     `json:"slug"`
 		Status    string     `json:"status"`
-		ExpiresAt *time.Time `json:"expires_at"`
+		ExpiresAt *time.Time `json:"expires_at"` // 省略时使用默认有效期
+		Permanent bool       `json:"permanent"`  // 不使用默认有效期，文章不过期
+		DomainID  *uint      `json:"domain_id"`
+		Theme     string     `json:"theme"`
+		SiteID    *uint      `json:"site_id"` // 仅不绑定站点的密钥可指定
+
+		Visibility string `json:"visibility"` // public, unlisted, password
+		Password   string `json:"password"`
+
+		ExpiryAction string `json:"expiry_action"` // unpublish, archive, redirect, delete
+		RedirectURL  string `json:"redirect_url"`
+
+		BlueprintID *uint             `json:"blueprint_id"`
+		Variables   map[string]string `json:"variables"` // 文章模板占位符的值
+
+		articleMetaRequest
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, N8nResponse{
-			Success: false,
-			Error:   err.Error(),
-		})
+		respondFailure(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	article, err := h.articleService.CreateArticle(req.Title, req.Content, req.Slug, req.Status, req.ExpiresAt)
+	siteID := auth.SiteScope(c)
+	if siteID == nil {
+		siteID = req.SiteID
+	}
+	articleService := h.articleService.ForSite(siteID)
+
+	expiresAt := req.ExpiresAt
+	if req.Permanent {
+		if expiresAt != nil {
+			respondFieldErrors(c, services.FieldErrors{"permanent": "permanent cannot be combined with expires_at"})
+			return
+		}
+	} else if expiresAt == nil {
+		var site uint
+		if siteID != nil {
+			site = *siteID
+		}
+		if ttl := h.defaultTTL(c, site); ttl > 0 {
+			t := time.Now().Add(ttl)
+			expiresAt = &t
+		}
+	}
+
+	article, err := articleService.CreateArticle(services.ArticleInput{
+		Title:     req.Title,
+		Content:   req.Content,
+		Slug:      req.Slug,
+		Status:    req.Status,
+		ExpiresAt: expiresAt,
+		DomainID:  req.DomainID,
+		Theme:     req.Theme,
+
+		Visibility: req.Visibility,
+		Password:   req.Password,
+
+		ExpiryAction: req.ExpiryAction,
+		RedirectURL:  req.RedirectURL,
+
+		BlueprintID: req.BlueprintID,
+		Variables:   req.Variables,
+
+		Meta: req.meta(),
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, N8nResponse{
-			Success: false,
-			Error:   err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
 	// 生成发布URL
 	publishURL := ""
 	if article.Status == "published" {
-		publishURL = h.cfg.Server.Domain + "/p/" + article.Slug
+		publishURL = h.articleService.PublicURL(article)
 	}
 
+	setArticleETag(c, article)
 	c.JSON(http.StatusCreated, N8nResponse{
 		Success: true,
 		Data:    article,
@@ -113,69 +312,95 @@ func (h *Handler) CreateArticle(c *gin.Context) {
 func (h *Handler) GetArticle(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, N8nResponse{
-			Success: false,
-			Error:   "Invalid article ID",
-		})
+		respondFailure(c, http.StatusBadRequest, "Invalid article ID")
 		return
 	}
 
-	article, err := h.articleService.GetArticleByID(id)
+	article, err := h.articles(c).GetArticleByID(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, N8nResponse{
-			Success: false,
-			Error:   "Article not found",
-		})
+		respondFailure(c, http.StatusNotFound, "Article not found")
 		return
 	}
 
+	setArticleETag(c, article)
 	c.JSON(http.StatusOK, N8nResponse{
 		Success: true,
 		Data:    article,
 	})
 }
 
-// 更新文章
+// 更新文章，带 If-Match 请求头时只在文章版本匹配时更新
 func (h *Handler) UpdateArticle(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, N8nResponse{
-			Success: false,
-			Error:   "Invalid article ID",
-		})
+		respondFailure(c, http.StatusBadRequest, "Invalid article ID")
 		return
 	}
 
 	var req struct {
 		Title     string     `json:"title"`
 		Content   string     `json:"content"`
+		Slug      string     `json:"slug"` // 修改后旧地址自动301跳转到新地址
 		Status    string     `json:"status"`
 		ExpiresAt *time.Time `json:"expires_at"`
+		DomainID  *uint      `json:"domain_id"`
+		Theme     string     `json:"theme"`
+
+		Visibility string `json:"visibility"`
+		Password   string `json:"password"`
+
+		ExpiryAction string `json:"expiry_action"`
+		RedirectURL  string `json:"redirect_url"`
+
+		articleMetaRequest
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, N8nResponse{
-			Success: false,
-			Error:   err.Error(),
-		})
+		respondFailure(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	article, err := h.articleService.UpdateArticle(id, req.Title, req.Content, req.Status, req.ExpiresAt)
+	version, ok := h.ifMatchVersion(c, id)
+	if !ok {
+		h.respondPreconditionFailed(c, id)
+		return
+	}
+
+	article, err := h.articles(c).UpdateArticle(id, services.ArticleInput{
+		Title:     req.Title,
+		Content:   req.Content,
+		Slug:      req.Slug,
+		Status:    req.Status,
+		ExpiresAt: req.ExpiresAt,
+		DomainID:  req.DomainID,
+		Theme:     req.Theme,
+
+		Visibility: req.Visibility,
+		Password:   req.Password,
+
+		ExpiryAction: req.ExpiryAction,
+		RedirectURL:  req.RedirectURL,
+
+		Meta: req.meta(),
+
+		ExpectedVersion: version,
+	})
+	if errors.Is(err, services.ErrEditConflict) {
+		h.respondPreconditionFailed(c, id)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, N8nResponse{
-			Success: false,
-			Error:   err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
 	// 生成发布URL
 	publishURL := ""
 	if article.Status == "published" {
-		publishURL = h.cfg.Server.Domain + "/p/" + article.Slug
+		publishURL = h.articleService.PublicURL(article)
 	}
 
+	setArticleETag(c, article)
 	c.JSON(http.StatusOK, N8nResponse{
 		Success: true,
 		Data:    article,
@@ -183,22 +408,33 @@ func (h *Handler) UpdateArticle(c *gin.Context) {
 	})
 }
 
-// 删除文章
+// 删除文章，带 If-Match 请求头时只在文章版本匹配时删除
 func (h *Handler) DeleteArticle(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
-		c.JSON(http.StatusBadRequest, N8nResponse{
-			Success: false,
-			Error:   "Invalid article ID",
-		})
+		respondFailure(c, http.StatusBadRequest, "Invalid article ID")
 		return
 	}
 
-	if err := h.articleService.DeleteArticle(id); err != nil {
-		c.JSON(http.StatusInternalServerError, N8nResponse{
-			Success: false,
-			Error:   err.Error(),
-		})
+	version, ok := h.ifMatchVersion(c, id)
+	if !ok {
+		h.respondPreconditionFailed(c, id)
+		return
+	}
+
+	// 指定 redirect_to 时文章原地址跳转到该地址
+	var err error
+	if target := c.Query("redirect_to"); target != "" {
+		err = h.articles(c).DeleteArticleWithRedirect(id, target, version)
+	} else {
+		err = h.articles(c).DeleteArticle(id, version)
+	}
+	if errors.Is(err, services.ErrEditConflict) {
+		h.respondPreconditionFailed(c, id)
+		return
+	}
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -209,23 +445,65 @@ func (h *Handler) DeleteArticle(c *gin.Context) {
 
 // 获取文章列表
 func (h *Handler) ListArticles(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	status := c.Query("status")
+	limit, err := parseLimit(c)
+	if err != nil {
+		respondFailure(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	fields, err := services.ParseArticleFields(c.Query("fields"))
+	if err != nil {
+		respondFailure(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	opts := services.ArticleListOptions{
+		Status: c.Query("status"),
+		Limit:  limit,
+		Fields: fields,
+	}
 
-	articles, total, err := h.articleService.ListArticles(page, limit, status)
+	// 传入 cursor 参数（可以为空）时使用游标翻页
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		h.listArticlesByCursor(c, cursor, opts)
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		respondFailure(c, http.StatusBadRequest, "page must be a positive integer")
+		return
+	}
+
+	articles, total, err := h.articles(c).ListArticles(page, opts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, N8nResponse{
-			Success: false,
-			Error:   err.Error(),
-		})
+		respondError(c, err)
+		return
+	}
+	data, err := selectArticleFields(articles, fields)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
+	lastPage := int((total + int64(limit) - 1) / int64(limit))
+	if lastPage < 1 {
+		lastPage = 1
+	}
+	links := map[string]map[string]string{
+		"first": {"page": "1"},
+		"last":  {"page": strconv.Itoa(lastPage)},
+	}
+	if page > 1 {
+		links["prev"] = map[string]string{"page": strconv.Itoa(page - 1)}
+	}
+	if page < lastPage {
+		links["next"] = map[string]string{"page": strconv.Itoa(page + 1)}
+	}
+	setLinkHeader(c, []string{"first", "prev", "next", "last"}, links)
+
 	c.JSON(http.StatusOK, N8nResponse{
 		Success: true,
 		Data: gin.H{
-			"articles": articles,
+			"articles": data,
 			"total":    total,
 			"page":     page,
 			"limit":    limit,
@@ -233,28 +511,36 @@ func (h *Handler) ListArticles(c *gin.Context) {
 	})
 }
 
-// 获取已发布的文章（公开访问）
-func (h *Handler) GetPublishedArticle(c *gin.Context) {
-	slug := c.Param("slug")
-
-	article, err := h.articleService.GetPublishedArticleBySlug(slug)
+// 按游标获取文章列表，next_cursor 为空表示没有更多文章
+func (h *Handler) listArticlesByCursor(c *gin.Context, cursor string, opts services.ArticleListOptions) {
+	articles, next, err := h.articles(c).ListArticlesAfter(cursor, opts)
 	if err != nil {
-		c.HTML(http.StatusNotFound, "404.html", gin.H{
-			"message": "Article not found",
-		})
+		if errors.Is(err, services.ErrInvalidCursor) {
+			respondFailure(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(c, err)
+		return
+	}
+	data, err := selectArticleFields(articles, opts.Fields)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	// 检查是否过期
-	if article.ExpiresAt != nil && article.ExpiresAt.Before(time.Now()) {
-		c.HTML(http.StatusGone, "expired.html", gin.H{
-			"message": "This article has expired",
+	if next != "" {
+		setLinkHeader(c, []string{"next"}, map[string]map[string]string{
+			"next": {"cursor": next},
 		})
-		return
 	}
 
-	c.HTML(http.StatusOK, "article.html", gin.H{
-		"article": article,
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+		Data: gin.H{
+			"articles":    data,
+			"limit":       opts.Limit,
+			"next_cursor": next,
+		},
 	})
 }
 
@@ -264,22 +550,37 @@ func (h *Handler) CreateAPIKey(c *gin.Context) {
 		Name        string     `json:"name" binding:"required"`
 		Permissions string     `json:"permissions"`
 		ExpiresAt   *time.Time `json:"expires_at"`
+		SiteID      *uint      `json:"site_id"`
+
+		DefaultTTLHours int `json:"default_ttl_hours"` // 0 表示使用站点设置
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, N8nResponse{
-			Success: false,
-			Error:   err.Error(),
-		})
+		respondFailure(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 绑定站点的密钥只能为本站点创建密钥
+	siteID := auth.SiteScope(c)
+	if siteID == nil {
+		siteID = req.SiteID
+	}
+
+	if req.DefaultTTLHours < 0 {
+		respondFieldErrors(c, services.FieldErrors{"default_ttl_hours": "default_ttl_hours must not be negative"})
 		return
 	}
 
-	apiKey, err := h.authService.GenerateAPIKey(req.Name, req.Permissions, req.ExpiresAt)
+	if siteID != nil {
+		if err := h.siteService.CheckSiteID(*siteID); err != nil {
+			respondError(c, err)
+			return
+		}
+	}
+
+	apiKey, err := h.authService.GenerateAPIKey(req.Name, req.Permissions, req.ExpiresAt, siteID, req.DefaultTTLHours)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, N8nResponse{
-			Success: false,
-			Error:   err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
@@ -289,16 +590,344 @@ func (h *Handler) CreateAPIKey(c *gin.Context) {
 	})
 }
 
+// 获取API密钥列表，绑定站点的密钥只能看到本站点的密钥。密钥本身只在创建时返回
 func (h *Handler) ListAPIKeys(c *gin.Context) {
-	// 实现API密钥列表逻辑
+	keys, err := h.authService.ListAPIKeys(auth.SiteScope(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	for i := range keys {
+		keys[i].Key = ""
+	}
+
 	c.JSON(http.StatusOK, N8nResponse{
 		Success: true,
-		Data:    []interface{}{},
+		Data:    keys,
 	})
 }
 
+// 停用API密钥，停用后的密钥立即无法通过认证
 func (h *Handler) DeleteAPIKey(c *gin.Context) {
-	// 实现删除API密钥逻辑
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondFailure(c, http.StatusBadRequest, "Invalid API key ID")
+		return
+	}
+
+	// 绑定站点的密钥只能停用本站点的密钥
+	key, err := h.authService.GetAPIKeyByID(uint(id))
+	scope := auth.SiteScope(c)
+	if err != nil || (scope != nil && (key.SiteID == nil || *key.SiteID != *scope)) {
+		respondFailure(c, http.StatusNotFound, "API key not found")
+		return
+	}
+
+	if err := h.authService.RevokeAPIKey(key.ID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+	})
+}
+
+// 添加自定义域名
+func (h *Handler) CreateDomain(c *gin.Context) {
+	var req struct {
+		Host   string `json:"host" binding:"required"`
+		SiteID *uint  `json:"site_id"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondFailure(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	domain, err := h.domainService.CreateDomain(req.Host, req.SiteID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, N8nResponse{
+		Success: true,
+		Data:    domain,
+	})
+}
+
+// 获取自定义域名列表
+func (h *Handler) ListDomains(c *gin.Context) {
+	domains, err := h.domainService.ListDomains(nil)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+		Data:    domains,
+	})
+}
+
+// 删除自定义域名
+func (h *Handler) DeleteDomain(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondFailure(c, http.StatusBadRequest, "Invalid domain ID")
+		return
+	}
+
+	if err := h.domainService.DeleteDomain(uint(id)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+	})
+}
+
+// 创建站点
+func (h *Handler) CreateSite(c *gin.Context) {
+	var req struct {
+		Name          string `json:"name" binding:"required"`
+		Domain        string `json:"domain"`
+		Theme         string `json:"theme"`
+		StoragePrefix string `json:"storage_prefix"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondFailure(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	site, err := h.siteService.CreateSite(req.Name, req.Domain, req.Theme, req.StoragePrefix)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, N8nResponse{
+		Success: true,
+		Data:    site,
+	})
+}
+
+// 获取站点列表
+func (h *Handler) ListSites(c *gin.Context) {
+	sites, err := h.siteService.ListSites()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+		Data:    sites,
+	})
+}
+
+// 删除站点
+func (h *Handler) DeleteSite(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondFailure(c, http.StatusBadRequest, "Invalid site ID")
+		return
+	}
+
+	if err := h.siteService.DeleteSite(uint(id)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+	})
+}
+
+// 设置站点主题
+func (h *Handler) SetSiteTheme(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondFailure(c, http.StatusBadRequest, "Invalid site ID")
+		return
+	}
+
+	var req struct {
+		Theme string `json:"theme"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondFailure(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	site, err := h.siteService.SetTheme(uint(id), req.Theme)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// 静态文件由后台任务按新主题重新生成
+	siteID := site.ID
+	queued, err := h.articleService.ForSite(&siteID).EnqueueRebuild()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, N8nResponse{
+		Success: true,
+		Data: gin.H{
+			"site":   site,
+			"queued": queued,
+		},
+	})
+}
+
+// 设置站点文章的默认有效期
+func (h *Handler) SetSiteDefaultTTL(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondFailure(c, http.StatusBadRequest, "Invalid site ID")
+		return
+	}
+
+	var req struct {
+		DefaultTTLHours int `json:"default_ttl_hours"` // 0 表示使用全局配置
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondFailure(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	site, err := h.siteService.SetDefaultTTL(uint(id), req.DefaultTTLHours)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+		Data:    site,
+	})
+}
+
+// 获取可用主题列表
+func (h *Handler) ListThemes(c *gin.Context) {
+	names, err := h.themeManager.List()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+		Data: gin.H{
+			"themes":  names,
+			"default": h.themeManager.DefaultName(),
+		},
+	})
+}
+
+// 校验主题模板
+func (h *Handler) ValidateTheme(c *gin.Context) {
+	if err := h.themeManager.Validate(c.Param("name")); err != nil {
+		respondFailure(c, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+	})
+}
+
+// 创建草稿预览链接
+func (h *Handler) CreatePreview(c *gin.Context) {
+	var req struct {
+		TTLHours int `json:"ttl_hours"` // 有效期（小时），默认24小时
+	}
+
+	// 请求体可以为空，使用默认有效期
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondFailure(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	article, err := h.articles(c).GetArticleByID(c.Param("id"))
+	if err != nil {
+		respondFailure(c, http.StatusNotFound, "Article not found")
+		return
+	}
+
+	preview, err := h.previewService.CreatePreview(article, time.Duration(req.TTLHours)*time.Hour)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	previewURL := h.previewService.URL(preview, article)
+	c.JSON(http.StatusCreated, N8nResponse{
+		Success: true,
+		Data:    preview,
+		URL:     previewURL,
+	})
+}
+
+// 获取文章的预览链接
+func (h *Handler) ListPreviews(c *gin.Context) {
+	article, err := h.articles(c).GetArticleByID(c.Param("id"))
+	if err != nil {
+		respondFailure(c, http.StatusNotFound, "Article not found")
+		return
+	}
+
+	previews, err := h.previewService.ListPreviews(article.ID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	items := make([]gin.H, 0, len(previews))
+	for i := range previews {
+		items = append(items, gin.H{
+			"preview": previews[i],
+			"url":     h.previewService.URL(&previews[i], article),
+		})
+	}
+
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+		Data:    items,
+	})
+}
+
+// 撤销预览链接
+func (h *Handler) RevokePreview(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondFailure(c, http.StatusBadRequest, "Invalid preview ID")
+		return
+	}
+
+	// 只能撤销当前密钥可访问的文章的预览链接
+	preview, err := h.previewService.GetPreviewByID(uint(id))
+	if err == nil {
+		_, err = h.articles(c).GetArticleByID(preview.ArticleID)
+	}
+	if err != nil {
+		respondFailure(c, http.StatusNotFound, "Preview not found")
+		return
+	}
+
+	if err := h.previewService.RevokePreview(preview.ID); err != nil {
+		respondError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, N8nResponse{
 		Success: true,
 	})
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"static-hosting-server/internal/jobs"
+	"static-hosting-server/internal/models"
+)
+
+func TestJobRetryAndDeadLetter(t *testing.T) {
+	e := newTestEnv(t)
+
+	calls := 0
+	succeed := false
+	e.app.Jobs.Register("test.flaky", func(payload []byte) error {
+		calls++
+		if string(payload) != `{"n":1}` {
+			t.Errorf("unexpected payload %s", payload)
+		}
+		if succeed {
+			return nil
+		}
+		return errors.New("boom")
+	})
+	if err := e.app.Jobs.Enqueue(e.db, "test.flaky", 0, map[string]int{"n": 1}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	job := func() models.Job {
+		var job models.Job
+		e.db.Where("type = ?", "test.flaky").First(&job)
+		return job
+	}
+
+	// 失败后按退避时间重试，到期前不会再次执行
+	e.runJobs()
+	first := job()
+	if first.Status != jobs.StatusPending || first.Attempts != 1 || first.LastError != "boom" {
+		t.Fatalf("unexpected job after first failure: %+v", first)
+	}
+	if !first.RunAt.After(time.Now()) {
+		t.Fatal("failed job should be scheduled for a later retry")
+	}
+	e.runJobs()
+	if calls != 1 {
+		t.Fatalf("job should wait for its backoff, ran %d times", calls)
+	}
+
+	for i := 0; i < first.MaxAttempts; i++ {
+		e.db.Model(&models.Job{}).Where("id = ?", first.ID).Update("run_at", time.Now().Add(-time.Second))
+		e.runJobs()
+	}
+	if calls != first.MaxAttempts {
+		t.Fatalf("expected %d attempts, got %d", first.MaxAttempts, calls)
+	}
+	if got := job(); got.Status != jobs.StatusDead {
+		t.Fatalf("expected dead job, got %q", got.Status)
+	}
+
+	failed, err := e.app.Jobs.ListFailed(nil)
+	if err != nil || len(failed) != 1 || failed[0].ID != first.ID {
+		t.Fatalf("dead job should be listed as failed: %+v (%v)", failed, err)
+	}
+	siteID := uint(1)
+	if failed, _ := e.app.Jobs.ListFailed(&siteID); len(failed) != 0 {
+		t.Fatal("failed jobs should be scoped to their site")
+	}
+
+	// 手动重试
+	succeed = true
+	if err := e.app.Jobs.Retry(first.ID); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	e.runJobs()
+	if got := job(); got.Status != jobs.StatusDone || got.LastError != "" {
+		t.Fatalf("retried job should succeed: %+v", got)
+	}
+	if err := e.app.Jobs.Retry(first.ID); err == nil {
+		t.Fatal("finished jobs cannot be retried")
+	}
+}
+
+func TestRenderJobUsesLatestState(t *testing.T) {
+	e := newTestEnv(t)
+
+	// 发布后立即撤回，两个任务执行后不应留下页面
+	article := e.createArticle(map[string]interface{}{
+		"title": "Flip", "content": "<p>x</p>", "slug": "flip", "status": "published",
+	})
+	e.api(http.MethodPut, "/api/articles/"+article.ID, map[string]interface{}{"status": "draft"}).expect(t, http.StatusOK)
+	e.runJobs()
+	if fileExists(e.staticFile("flip")) {
+		t.Fatal("unpublished article should not keep static files")
+	}
+
+	// 删除时设置跳转，删除任务执行后保留跳转页
+	e.api(http.MethodPut, "/api/articles/"+article.ID, map[string]interface{}{"status": "published"}).expect(t, http.StatusOK)
+	e.runJobs()
+	e.api(http.MethodDelete, "/api/articles/"+article.ID+"?redirect_to=/p/elsewhere", nil).expect(t, http.StatusOK)
+	e.runJobs()
+	stub, err := os.ReadFile(e.staticFile("flip"))
+	if err != nil || !strings.Contains(string(stub), "/p/elsewhere") {
+		t.Fatalf("redirect stub should survive the remove job, got %q (%v)", stub, err)
+	}
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OpenAPI 3 接口文档，修改 /api 路由时需要同步更新
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// 浏览接口文档的页面
+//
+//go:embed docs.html
+var docsPage []byte
+
+// 获取OpenAPI文档
+func (h *Handler) GetOpenAPISpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", openAPISpec)
+}
+
+// 接口文档页面
+func (h *Handler) APIDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", docsPage)
+}
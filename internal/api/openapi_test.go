@@ -0,0 +1,40 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var routeParamPattern = regexp.MustCompile(`:([A-Za-z_]+)`)
+
+// 每个 /api 路由都需要在 OpenAPI 文档中描述
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	e := newTestEnv(t)
+
+	var spec struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	resp := e.request(http.MethodGet, "/api/openapi.json", "", nil).expect(t, http.StatusOK)
+	if err := json.Unmarshal([]byte(resp.Body), &spec); err != nil {
+		t.Fatalf("invalid OpenAPI document: %v", err)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		t.Fatalf("unexpected OpenAPI version %q", spec.OpenAPI)
+	}
+
+	for _, route := range e.router.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/") || route.Path == "/api/openapi.json" || route.Path == "/api/docs" {
+			continue
+		}
+		path := routeParamPattern.ReplaceAllString(strings.TrimPrefix(route.Path, "/api"), "{$1}")
+		if _, ok := spec.Paths[path][strings.ToLower(route.Method)]; !ok {
+			t.Errorf("%s %s is not documented in openapi.json", route.Method, path)
+		}
+	}
+
+	e.request(http.MethodGet, "/api/docs", "", nil).expect(t, http.StatusOK)
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"static-hosting-server/internal/models"
+	"static-hosting-server/internal/services"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 解析 limit 参数，缺省时使用默认值，超过上限时按上限处理
+func parseLimit(c *gin.Context) (int, error) {
+	value := c.Query("limit")
+	if value == "" {
+		return services.DefaultPageSize, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 {
+		return 0, fmt.Errorf("limit must be a positive integer (max %d)", services.MaxPageSize)
+	}
+	if limit > services.MaxPageSize {
+		limit = services.MaxPageSize
+	}
+	return limit, nil
+}
+
+// 只保留请求的字段，fields为空时原样返回
+func selectArticleFields(articles []models.Article, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return articles, nil
+	}
+
+	result := make([]map[string]interface{}, 0, len(articles))
+	for i := range articles {
+		raw, err := json.Marshal(&articles[i])
+		if err != nil {
+			return nil, err
+		}
+		var all map[string]interface{}
+		if err := json.Unmarshal(raw, &all); err != nil {
+			return nil, err
+		}
+
+		item := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			item[field] = all[field]
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// 设置 Link 响应头，links 为 rel 到查询参数修改的映射，按 rels 的顺序输出
+func setLinkHeader(c *gin.Context, rels []string, links map[string]map[string]string) {
+	var parts []string
+	for _, rel := range rels {
+		params, ok := links[rel]
+		if !ok {
+			continue
+		}
+		query := c.Request.URL.Query()
+		for key, value := range params {
+			if value == "" {
+				query.Del(key)
+			} else {
+				query.Set(key, value)
+			}
+		}
+		link := url.URL{Path: c.Request.URL.Path, RawQuery: query.Encode()}
+		parts = append(parts, fmt.Sprintf("<%s>; rel=\"%s\"", link.String(), rel))
+	}
+	if len(parts) > 0 {
+		c.Header("Link", strings.Join(parts, ", "))
+	}
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"static-hosting-server/internal/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 部分更新文章（JSON Merge Patch，RFC 7396）：省略的字段不修改，null 清除字段，
+// 带 If-Match 请求头时只在文章版本匹配时更新
+func (h *Handler) PatchArticle(c *gin.Context) {
+	id := c.Param("id")
+
+	raw, err := c.GetRawData()
+	if err != nil {
+		respondFailure(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	var patch map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &patch); err != nil || patch == nil {
+		respondFailure(c, http.StatusBadRequest, "request body must be a JSON object")
+		return
+	}
+
+	input, fieldErrors := decodeArticlePatch(patch)
+	if len(fieldErrors) > 0 {
+		respondFieldErrors(c, fieldErrors)
+		return
+	}
+
+	version, ok := h.ifMatchVersion(c, id)
+	if !ok {
+		h.respondPreconditionFailed(c, id)
+		return
+	}
+	input.ExpectedVersion = version
+
+	article, err := h.articles(c).UpdateArticle(id, input)
+	if errors.Is(err, services.ErrEditConflict) {
+		h.respondPreconditionFailed(c, id)
+		return
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	publishURL := ""
+	if article.Status == "published" {
+		publishURL = h.articleService.PublicURL(article)
+	}
+
+	setArticleETag(c, article)
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+		Data:    article,
+		URL:     publishURL,
+	})
+}
+
+// 不能通过接口修改的文章字段
+var readOnlyArticleFields = map[string]bool{
+	"id": true, "site_id": true, "version": true, "warned_at": true, "created_at": true, "updated_at": true,
+}
+
+// 把合并补丁转换为更新参数，同时检查每个字段的类型和是否允许清除
+func decodeArticlePatch(patch map[string]json.RawMessage) (services.ArticleInput, services.FieldErrors) {
+	var input services.ArticleInput
+	fieldErrors := services.FieldErrors{}
+
+	// 解析字符串字段，null 返回 nil
+	str := func(field string, value json.RawMessage) *string {
+		if string(value) == "null" {
+			return nil
+		}
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			fieldErrors[field] = "must be a string"
+			return nil
+		}
+		return &s
+	}
+	// 必填字段不能为 null 或空字符串
+	required := func(field string, value json.RawMessage) string {
+		s := str(field, value)
+		if _, invalid := fieldErrors[field]; invalid {
+			return ""
+		}
+		if s == nil || *s == "" {
+			fieldErrors[field] = "cannot be cleared"
+			return ""
+		}
+		return *s
+	}
+	// 可清除的字段：null 或空字符串表示清除
+	clearable := func(field string, value json.RawMessage) string {
+		s := str(field, value)
+		if _, invalid := fieldErrors[field]; invalid {
+			return ""
+		}
+		if s == nil || *s == "" {
+			input.Clear = append(input.Clear, field)
+			return ""
+		}
+		return *s
+	}
+	// SEO字段：null 与空字符串相同，表示清除
+	meta := func(field string, value json.RawMessage) *string {
+		if s := str(field, value); s != nil {
+			return s
+		}
+		empty := ""
+		return &empty
+	}
+
+	for field, value := range patch {
+		switch field {
+		case "title":
+			input.Title = required(field, value)
+		case "slug":
+			input.Slug = required(field, value)
+		case "status":
+			input.Status = required(field, value)
+			if input.Status != "" && !services.ValidArticleStatus(input.Status) {
+				fieldErrors[field] = "must be one of draft, published, archived, expired"
+			}
+		case "content":
+			input.Content = clearable(field, value)
+		case "theme":
+			input.Theme = clearable(field, value)
+		case "visibility":
+			input.Visibility = clearable(field, value)
+		case "password":
+			input.Password = clearable(field, value)
+		case "expiry_action":
+			input.ExpiryAction = clearable(field, value)
+		case "redirect_url":
+			input.RedirectURL = clearable(field, value)
+		case "expires_at":
+			if string(value) == "null" {
+				input.Clear = append(input.Clear, field)
+				continue
+			}
+			var expiresAt time.Time
+			if err := json.Unmarshal(value, &expiresAt); err != nil {
+				fieldErrors[field] = "must be an RFC 3339 timestamp or null"
+				continue
+			}
+			input.ExpiresAt = &expiresAt
+		case "domain_id":
+			if string(value) == "null" {
+				input.Clear = append(input.Clear, field)
+				continue
+			}
+			var domainID uint
+			if err := json.Unmarshal(value, &domainID); err != nil {
+				fieldErrors[field] = "must be a domain ID or null"
+				continue
+			}
+			input.DomainID = &domainID
+		case "description":
+			input.Meta.Description = meta(field, value)
+		case "canonical_url":
+			input.Meta.CanonicalURL = meta(field, value)
+		case "cover_image":
+			input.Meta.CoverImage = meta(field, value)
+		case "author":
+			input.Meta.Author = meta(field, value)
+		case "og_title":
+			input.Meta.OGTitle = meta(field, value)
+		case "twitter_card":
+			input.Meta.TwitterCard = meta(field, value)
+		default:
+			if readOnlyArticleFields[field] {
+				fieldErrors[field] = "is read-only"
+			} else {
+				fieldErrors[field] = "unknown field"
+			}
+		}
+	}
+	return input, fieldErrors
+}
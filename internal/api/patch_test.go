@@ -0,0 +1,109 @@
+package api
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"static-hosting-server/internal/models"
+)
+
+func TestPatchArticleMergeSemantics(t *testing.T) {
+	e := newTestEnv(t)
+	expiresAt := time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)
+	created := e.createArticle(map[string]interface{}{
+		"title": "Patched", "content": "<p>Body</p>", "slug": "patched", "status": "published",
+		"expires_at": expiresAt, "visibility": "password", "password": "secret", "description": "Summary",
+	})
+
+	patch := func(body interface{}) *testResponse {
+		return e.requestWithHeader(http.MethodPatch, "/api/articles/"+created.ID, testAPIKey,
+			http.Header{"Content-Type": {"application/merge-patch+json"}}, body)
+	}
+
+	// 省略的字段不修改，null 清除字段
+	var article models.Article
+	resp := patch(map[string]interface{}{
+		"title": "Patched again", "expires_at": nil, "visibility": nil, "description": nil,
+	}).expect(t, http.StatusOK)
+	resp.decode(t, &article)
+	if article.Title != "Patched again" || article.Content != "<p>Body</p>" || article.Slug != "patched" {
+		t.Fatalf("omitted fields should be kept: %+v", article)
+	}
+	if article.ExpiresAt != nil || article.Visibility != "public" || article.Description != "" {
+		t.Fatalf("null should clear fields, got expires_at=%v visibility=%q description=%q",
+			article.ExpiresAt, article.Visibility, article.Description)
+	}
+	if article.Version != 2 || resp.Header.Get("ETag") != `"2"` {
+		t.Fatalf("expected version 2, got %d (ETag %q)", article.Version, resp.Header.Get("ETag"))
+	}
+	if resp.URL == "" {
+		t.Fatal("published article should return its public URL")
+	}
+
+	patch(map[string]interface{}{"content": nil}).expect(t, http.StatusOK).decode(t, &article)
+	if article.Content != "" {
+		t.Fatalf("content should be cleared, got %q", article.Content)
+	}
+
+	// 所有字段错误一次返回，文章不修改
+	invalid := patch(map[string]interface{}{
+		"title": nil, "status": "deleted", "expires_at": "tomorrow", "version": 9, "colour": "red",
+	}).expect(t, http.StatusUnprocessableEntity)
+	for _, field := range []string{"title", "status", "expires_at", "version", "colour"} {
+		if invalid.Errors[field] == "" {
+			t.Errorf("expected an error for %s, got %v", field, invalid.Errors)
+		}
+	}
+	patch(map[string]interface{}{"visibility": "password"}).expect(t, http.StatusUnprocessableEntity)
+	if current, _ := e.app.Articles.GetArticleByID(created.ID); current.Version != article.Version {
+		t.Fatal("rejected patches must not modify the article")
+	}
+
+	patch([]string{"title"}).expect(t, http.StatusBadRequest)
+	e.requestWithHeader(http.MethodPatch, "/api/articles/missing", testAPIKey, nil,
+		map[string]interface{}{"title": "x"}).expect(t, http.StatusNotFound)
+}
+
+func TestPatchArticleIfMatch(t *testing.T) {
+	e := newTestEnv(t)
+	created := e.createArticle(map[string]interface{}{
+		"title": "Guarded", "content": "<p>v1</p>", "slug": "guarded", "status": "draft",
+	})
+
+	e.requestWithHeader(http.MethodPatch, "/api/articles/"+created.ID, testAPIKey, http.Header{"If-Match": {`"1"`}},
+		map[string]interface{}{"content": "<p>v2</p>"}).expect(t, http.StatusOK)
+
+	var current models.Article
+	e.requestWithHeader(http.MethodPatch, "/api/articles/"+created.ID, testAPIKey, http.Header{"If-Match": {`"1"`}},
+		map[string]interface{}{"content": "<p>stale</p>"}).expect(t, http.StatusPreconditionFailed).decode(t, &current)
+	if current.Content != "<p>v2</p>" {
+		t.Fatalf("412 should return the current article, got %q", current.Content)
+	}
+}
+
+func TestUpdateArticleToArchived(t *testing.T) {
+	e := newTestEnv(t)
+	put := e.createArticle(map[string]interface{}{"title": "Put", "content": "<p>1</p>", "slug": "put", "status": "published"})
+	patched := e.createArticle(map[string]interface{}{"title": "Patch", "content": "<p>2</p>", "slug": "patch", "status": "published"})
+
+	e.api(http.MethodPut, "/api/articles/"+put.ID, map[string]interface{}{"status": "archived"}).expect(t, http.StatusOK)
+	e.requestWithHeader(http.MethodPatch, "/api/articles/"+patched.ID, testAPIKey,
+		http.Header{"Content-Type": {"application/merge-patch+json"}}, map[string]interface{}{"status": "archived"}).
+		expect(t, http.StatusOK)
+	e.runJobs()
+
+	// 手动归档与过期归档相同：访问路由和静态文件都保留页面并显示归档提示
+	for _, slug := range []string{"put", "patch"} {
+		live := e.request(http.MethodGet, "/p/"+slug, "", nil).expect(t, http.StatusOK)
+		if !strings.Contains(live.Body, "已归档") {
+			t.Fatalf("archived article %s should be served with the archive banner", slug)
+		}
+		static, err := os.ReadFile(e.staticFile(slug))
+		if err != nil || !strings.Contains(string(static), "已归档") {
+			t.Fatalf("static page of %s should show the archive banner: %v", slug, err)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"static-hosting-server/internal/models"
+	"static-hosting-server/internal/services"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 解锁密码保护文章后Cookie的有效期
+const accessCookieMaxAge = 3600 * 24
+
+// 公开请求对应的站点
+type publicSite struct {
+	domain   *models.Domain // 按Host匹配到的自定义域名，默认域名为nil
+	siteID   uint
+	basePath string // 通过 /s/<存储前缀> 访问未绑定域名的站点时的路径前缀
+}
+
+// 按Host路由：站点域名提供该站点的内容；默认域名下 /s/<存储前缀>/... 提供对应站点的内容，
+// 其他请求使用默认站点。返回站点和站内路径，路径中的站点不存在时返回false
+func (h *Handler) resolveSite(c *gin.Context) (publicSite, string, bool) {
+	site := publicSite{domain: h.domainService.ResolveHost(c.Request.Host)}
+	path := c.Request.URL.Path
+	if site.domain != nil {
+		if site.domain.SiteID != nil {
+			site.siteID = *site.domain.SiteID
+		}
+		return site, path, true
+	}
+
+	prefix, rest, ok := services.SplitSitePath(path)
+	if !ok {
+		return site, path, true
+	}
+	siteID, ok := h.siteService.SiteByPath(prefix)
+	if !ok {
+		return site, path, false
+	}
+	site.siteID = siteID
+	site.basePath = h.siteService.SitePath(siteID)
+	return site, rest, true
+}
+
+// 站内跳转目标补上站点的路径前缀
+func (site publicSite) target(target string) string {
+	if site.basePath != "" && strings.HasPrefix(target, "/") {
+		return site.basePath + target
+	}
+	return target
+}
+
+// 按请求的Host和slug查找可公开访问的文章，未找到或需要跳转时已写入响应并返回false
+func (h *Handler) resolvePublishedArticle(c *gin.Context) (*models.Article, publicSite, bool) {
+	slug := c.Param("slug")
+	site, path, ok := h.resolveSite(c)
+	if !ok {
+		c.HTML(http.StatusNotFound, "404.html", gin.H{
+			"message": "Article not found",
+		})
+		return nil, site, false
+	}
+	domain, siteID := site.domain, site.siteID
+
+	article, err := h.articleService.ForSite(&siteID).GetPublishedArticleBySlug(slug)
+	if err != nil {
+		// 文章改名或删除后按跳转表跳转
+		if redirect, ok := h.redirectService.Resolve(siteID, path); ok {
+			c.Redirect(redirect.StatusCode, site.target(redirect.Target))
+			return nil, site, false
+		}
+		c.HTML(http.StatusNotFound, "404.html", gin.H{
+			"message": "Article not found",
+		})
+		return nil, site, false
+	}
+
+	// 未绑定站点的自定义域名只提供映射到该域名的文章
+	if domain != nil && domain.SiteID == nil && (article.DomainID == nil || *article.DomainID != domain.ID) {
+		c.HTML(http.StatusNotFound, "404.html", gin.H{
+			"message": "Article not found",
+		})
+		return nil, site, false
+	}
+	if domain == nil && h.domainService.ArticleDomain(article) != nil {
+		// 通过默认域名访问绑定了自定义域名（或所在站点已绑定域名）的文章时跳转到规范地址
+		c.Redirect(http.StatusMovedPermanently, h.articleService.PublicURL(article))
+		return nil, site, false
+	}
+
+	// 归档的文章继续展示，页面中显示归档提示
+	if services.IsArchived(article) {
+		return article, site, true
+	}
+
+	// 检查是否过期，按文章的过期策略处理
+	if services.IsExpired(article) {
+		if article.ExpiryAction == services.ExpiryRedirect {
+			c.Redirect(http.StatusFound, article.RedirectURL)
+			return nil, site, false
+		}
+		c.HTML(http.StatusGone, "expired.html", gin.H{
+			"message": "This article has expired",
+		})
+		return nil, site, false
+	}
+
+	return article, site, true
+}
+
+// 按请求的Host返回对应站点的站点地图，由定时任务生成
+func (h *Handler) GetSitemap(c *gin.Context) {
+	site, _, ok := h.resolveSite(c)
+	path := h.articleService.SitemapPath(site.siteID)
+	if _, err := os.Stat(path); !ok || err != nil {
+		c.HTML(http.StatusNotFound, "404.html", gin.H{
+			"message": "Page not found",
+		})
+		return
+	}
+
+	c.Header("Content-Type", "application/xml; charset=utf-8")
+	c.File(path)
+}
+
+// 获取已发布的文章（公开访问）
+func (h *Handler) GetPublishedArticle(c *gin.Context) {
+	article, _, ok := h.resolvePublishedArticle(c)
+	if !ok {
+		return
+	}
+
+	if !services.IsIndexable(article) {
+		c.Header("X-Robots-Tag", "noindex, nofollow")
+	}
+
+	// 密码保护的文章需要先解锁
+	if services.IsPasswordProtected(article) {
+		token, _ := c.Cookie(services.AccessCookieName(article))
+		if !h.articleService.CheckAccessToken(article, token) {
+			c.Header("Cache-Control", "no-store")
+			c.HTML(http.StatusUnauthorized, "article_password.html", gin.H{
+				"article": article,
+			})
+			return
+		}
+		c.Header("Cache-Control", "private, no-store")
+	}
+
+	// 与静态文件使用相同的主题渲染流程
+	var buf bytes.Buffer
+	if err := h.articleService.RenderArticle(&buf, article, nil); err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
+			"error": "Failed to render article",
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// 输入密码解锁文章
+func (h *Handler) UnlockArticle(c *gin.Context) {
+	article, site, ok := h.resolvePublishedArticle(c)
+	if !ok {
+		return
+	}
+
+	articlePath := site.target(services.ArticlePath(article.Slug))
+	if !services.IsPasswordProtected(article) {
+		c.Redirect(http.StatusFound, articlePath)
+		return
+	}
+
+	if !h.articleService.CheckArticlePassword(article, c.PostForm("password")) {
+		c.Header("X-Robots-Tag", "noindex, nofollow")
+		c.Header("Cache-Control", "no-store")
+		c.HTML(http.StatusUnauthorized, "article_password.html", gin.H{
+			"article": article,
+			"error":   "密码错误",
+		})
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(services.AccessCookieName(article), h.articleService.AccessToken(article),
+		accessCookieMaxAge, articlePath, "", c.Request.TLS != nil, true)
+	c.Redirect(http.StatusFound, articlePath)
+}
+
+// 通过预览链接访问文章（包括草稿）
+func (h *Handler) GetPreview(c *gin.Context) {
+	// 预览页面不允许被搜索引擎收录
+	c.Header("X-Robots-Tag", "noindex, nofollow")
+
+	article, err := h.previewService.ResolveToken(c.Param("token"))
+	if err != nil {
+		c.HTML(http.StatusNotFound, "404.html", gin.H{
+			"message": "Preview link is invalid or has expired",
+		})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := h.articleService.RenderArticle(&buf, article, map[string]interface{}{
+		"preview": true,
+		"noindex": true,
+	}); err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
+			"error": "Failed to render article",
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// 未匹配路由的请求，命中跳转表时跳转，否则返回404
+func (h *Handler) ServeRedirect(c *gin.Context) {
+	if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+		if site, path, ok := h.resolveSite(c); ok {
+			if redirect, ok := h.redirectService.Resolve(site.siteID, path); ok {
+				c.Redirect(redirect.StatusCode, site.target(redirect.Target))
+				return
+			}
+		}
+	}
+
+	c.HTML(http.StatusNotFound, "404.html", gin.H{
+		"message": "Page not found",
+	})
+}
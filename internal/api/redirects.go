@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/http"
+	"static-hosting-server/internal/auth"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 创建跳转
+func (h *Handler) CreateRedirect(c *gin.Context) {
+	var req struct {
+		SourcePath string `json:"source_path" binding:"required"`
+		Target     string `json:"target" binding:"required"`
+		StatusCode int    `json:"status_code"` // 301（默认）, 302, 307, 308
+		SiteID     *uint  `json:"site_id"`     // 仅不绑定站点的密钥可指定
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondFailure(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var siteID uint
+	if scope := auth.SiteScope(c); scope != nil {
+		siteID = *scope
+	} else if req.SiteID != nil {
+		siteID = *req.SiteID
+	}
+
+	redirect, err := h.redirectService.CreateRedirect(siteID, req.SourcePath, req.Target, req.StatusCode)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, N8nResponse{
+		Success: true,
+		Data:    redirect,
+	})
+}
+
+// 获取跳转列表
+func (h *Handler) ListRedirects(c *gin.Context) {
+	redirects, err := h.redirectService.ListRedirects(auth.SiteScope(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+		Data:    redirects,
+	})
+}
+
+// 删除跳转
+func (h *Handler) DeleteRedirect(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondFailure(c, http.StatusBadRequest, "Invalid redirect ID")
+		return
+	}
+
+	// 绑定站点的密钥只能删除本站点的跳转
+	redirect, err := h.redirectService.GetRedirectByID(uint(id))
+	scope := auth.SiteScope(c)
+	if err != nil || (scope != nil && redirect.SiteID != *scope) {
+		respondFailure(c, http.StatusNotFound, "Redirect not found")
+		return
+	}
+
+	if err := h.redirectService.DeleteRedirect(redirect.ID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+	})
+}
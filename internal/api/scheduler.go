@@ -0,0 +1,57 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"static-hosting-server/internal/models"
+	"static-hosting-server/internal/scheduler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 定时任务及其最近一次执行记录
+type schedulerJob struct {
+	scheduler.Definition
+	LastRun *models.SchedulerRun `json:"last_run"`
+}
+
+// 获取定时任务列表
+func (h *Handler) ListSchedulerJobs(c *gin.Context) {
+	lastRuns, err := scheduler.LastRuns(h.db)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var result []schedulerJob
+	for _, definition := range scheduler.Definitions(h.cfg) {
+		job := schedulerJob{Definition: definition}
+		if run, ok := lastRuns[definition.Name]; ok {
+			job.LastRun = &run
+		}
+		result = append(result, job)
+	}
+
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+		Data:    result,
+	})
+}
+
+// 立即执行定时任务，任务由后台任务队列异步执行
+func (h *Handler) RunSchedulerJob(c *gin.Context) {
+	name := c.Param("name")
+	if err := scheduler.Trigger(h.cfg, h.jobQueue, name); err != nil {
+		if errors.Is(err, scheduler.ErrUnknownJob) {
+			respondFailure(c, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, N8nResponse{
+		Success: true,
+		Data:    gin.H{"name": name, "queued": true},
+	})
+}
@@ -0,0 +1,237 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"static-hosting-server/internal/models"
+	"static-hosting-server/internal/scheduler"
+
+	"gorm.io/gorm"
+)
+
+func TestSchedulerLeaderElection(t *testing.T) {
+	e := newTestEnv(t)
+
+	first := scheduler.NewLeader(e.db, time.Minute)
+	second := scheduler.NewLeader(e.db, time.Minute)
+	first.Start()
+	second.Start()
+	defer second.Stop()
+
+	if !first.IsLeader() || second.IsLeader() {
+		t.Fatalf("exactly one node should lead: first=%v second=%v", first.IsLeader(), second.IsLeader())
+	}
+	if lease := scheduler.CurrentLeader(e.db); lease == nil || lease.Holder != first.ID() {
+		t.Fatalf("lease should be held by the first node, got %+v", lease)
+	}
+
+	// 主节点退出时释放租约，其他节点在下次续约时接管
+	first.Stop()
+	if first.IsLeader() {
+		t.Fatal("stopped node should not lead")
+	}
+	second.Stop()
+	second.Start()
+	if !second.IsLeader() {
+		t.Fatal("second node should take over the released lease")
+	}
+
+	// 持有者无法续约时，租约过期后被接管
+	third := scheduler.NewLeader(e.db, time.Minute)
+	e.db.Model(&models.SchedulerLease{}).Where("1 = 1").Update("expires_at", time.Now().Add(-time.Second))
+	third.Start()
+	defer third.Stop()
+	if !third.IsLeader() {
+		t.Fatal("expired lease should be taken over")
+	}
+}
+
+// 启动定时任务，测试结束时停止
+func (e *testEnv) startScheduler() *scheduler.Scheduler {
+	e.t.Helper()
+	s := scheduler.Start(e.app)
+	e.t.Cleanup(s.Stop)
+	return s
+}
+
+func TestSchedulerRunNow(t *testing.T) {
+	e := newTestEnv(t)
+	e.startScheduler()
+
+	article := e.createArticle(map[string]interface{}{
+		"title": "Mapped", "content": `<p><img src="/uploads/kept.png"></p>`, "slug": "mapped", "status": "published",
+	})
+
+	// 手动触发的任务加入后台任务队列
+	e.api(http.MethodPost, "/api/scheduler/jobs/sitemap/run", nil).expect(t, http.StatusAccepted)
+	e.api(http.MethodPost, "/api/scheduler/jobs/missing/run", nil).expect(t, http.StatusNotFound)
+	e.runJobs()
+
+	resp := e.request(http.MethodGet, "/sitemap.xml", "", nil).expect(t, http.StatusOK)
+	if !strings.Contains(resp.Body, "http://blog.example.test/p/"+article.Slug) {
+		t.Fatalf("sitemap should list the published article:\n%s", resp.Body)
+	}
+
+	var jobs []struct {
+		Name    string               `json:"name"`
+		LastRun *models.SchedulerRun `json:"last_run"`
+	}
+	e.api(http.MethodGet, "/api/scheduler/jobs", nil).expect(t, http.StatusOK).decode(t, &jobs)
+	for _, job := range jobs {
+		if job.Name != "sitemap" {
+			continue
+		}
+		if job.LastRun == nil || job.LastRun.FinishedAt == nil || job.LastRun.Items != 1 || job.LastRun.Error != "" {
+			t.Fatalf("unexpected sitemap run: %+v", job.LastRun)
+		}
+	}
+
+	// 清理没有被引用的上传文件，保留文章中使用的文件
+	old := time.Now().Add(-48 * time.Hour)
+	for _, name := range []string{"kept.png", "orphan.png"} {
+		path := filepath.Join(e.cfg.Storage.UploadsPath, name)
+		os.WriteFile(path, []byte("png"), 0644)
+		os.Chtimes(path, old, old)
+	}
+	e.api(http.MethodPost, "/api/scheduler/jobs/orphan_media/run", nil).expect(t, http.StatusAccepted)
+	e.runJobs()
+	if !fileExists(filepath.Join(e.cfg.Storage.UploadsPath, "kept.png")) {
+		t.Fatal("referenced upload should be kept")
+	}
+	if fileExists(filepath.Join(e.cfg.Storage.UploadsPath, "orphan.png")) {
+		t.Fatal("orphan upload should be removed")
+	}
+}
+
+func TestSchedulerTaskLock(t *testing.T) {
+	e := newTestEnv(t)
+	e.startScheduler()
+
+	lastRun := func() models.SchedulerRun {
+		var run models.SchedulerRun
+		e.db.Where("name = ?", "sitemap").Order("id DESC").First(&run)
+		return run
+	}
+
+	// 其他节点正在执行同一任务时，手动触发的执行被跳过并记录
+	e.db.Create(&models.SchedulerLease{Name: "task:sitemap", Holder: "other-node", ExpiresAt: time.Now().Add(time.Minute)})
+	e.api(http.MethodPost, "/api/scheduler/jobs/sitemap/run", nil).expect(t, http.StatusAccepted)
+	e.runJobs()
+	if run := lastRun(); !run.Skipped || !strings.Contains(run.Error, "other-node") {
+		t.Fatalf("overlapping run should be recorded as skipped: %+v", run)
+	}
+
+	// 租约过期后可以执行，执行结束时释放
+	e.db.Model(&models.SchedulerLease{}).Where("name = ?", "task:sitemap").Update("expires_at", time.Now().Add(-time.Second))
+	e.api(http.MethodPost, "/api/scheduler/jobs/sitemap/run", nil).expect(t, http.StatusAccepted)
+	e.runJobs()
+	if run := lastRun(); run.Skipped || run.FinishedAt == nil || run.Error != "" {
+		t.Fatalf("run should execute once the lock is free: %+v", run)
+	}
+	var lease models.SchedulerLease
+	e.db.Where("name = ?", "task:sitemap").First(&lease)
+	if lease.ExpiresAt.After(time.Now()) {
+		t.Fatalf("lock should be released after the run: %+v", lease)
+	}
+
+	// 本节点上正在执行的同一任务同样阻止重叠执行，结束的执行不会释放其他执行持有的锁
+	node := scheduler.CurrentLeader(e.db).Holder
+	e.db.Model(&models.SchedulerLease{}).Where("name = ?", "task:sitemap").
+		Updates(map[string]interface{}{"holder": node, "expires_at": time.Now().Add(time.Minute)})
+	e.api(http.MethodPost, "/api/scheduler/jobs/sitemap/run", nil).expect(t, http.StatusAccepted)
+	e.runJobs()
+	if run := lastRun(); !run.Skipped || run.Holder != node {
+		t.Fatalf("overlapping run on the same node should be skipped: %+v", run)
+	}
+	e.db.Where("name = ?", "task:sitemap").First(&lease)
+	if lease.Holder != node || !lease.ExpiresAt.After(time.Now()) {
+		t.Fatalf("skipped run should leave the running lock alone: %+v", lease)
+	}
+
+	page := e.admin(http.MethodGet, "/admin/scheduler", nil).expect(t, http.StatusOK)
+	if !strings.Contains(page.Body, "已跳过") {
+		t.Fatal("scheduler page should show skipped runs")
+	}
+}
+
+func TestPreciseExpiry(t *testing.T) {
+	e := newTestEnv(t)
+	e.startScheduler()
+
+	article := e.createArticle(map[string]interface{}{
+		"title": "Short lived", "content": "<p>soon gone</p>", "status": "published",
+		"expires_at": time.Now().Add(1500 * time.Millisecond),
+	})
+
+	// 不等待每小时一次的清理任务
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var current models.Article
+		e.db.First(&current, "id = ?", article.ID)
+		if current.Status == "expired" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("article was not expired on time, status %q", current.Status)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	var run models.SchedulerRun
+	if err := e.db.Where("name = ?", "expire_due").First(&run).Error; err != nil || run.Items != 1 {
+		t.Fatalf("expiry run should be recorded: %+v (%v)", run, err)
+	}
+}
+
+func TestPreciseExpiryRetryBackoff(t *testing.T) {
+	e := newTestEnv(t)
+	e.startScheduler()
+
+	// 模拟一篇始终无法下线的文章
+	stuck := e.createArticle(map[string]interface{}{"title": "Stuck", "content": "<p>1</p>", "status": "published",
+		"expires_at": time.Now().Add(time.Hour)})
+	var attempts atomic.Int32
+	e.db.Callback().Update().Before("gorm:update").Register("test:fail_expiry", func(db *gorm.DB) {
+		article, ok := db.Statement.Model.(*models.Article)
+		updates, _ := db.Statement.Dest.(map[string]interface{})
+		if ok && article.ID == stuck.ID && updates["status"] != nil {
+			attempts.Add(1)
+			db.AddError(errors.New("storage unavailable"))
+		}
+	})
+	e.db.Model(&models.Article{}).Where("id = ?", stuck.ID).Update("expires_at", time.Now().Add(-time.Minute))
+
+	// 失败的文章不影响之后到期的文章
+	article := e.createArticle(map[string]interface{}{"title": "Short lived", "content": "<p>2</p>", "status": "published",
+		"expires_at": time.Now().Add(1500 * time.Millisecond)})
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var current models.Article
+		e.db.First(&current, "id = ?", article.ID)
+		if current.Status == "expired" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("article was not expired on time, status %q", current.Status)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	time.Sleep(2 * time.Second)
+
+	// 失败后退避重试，没有处理任何文章的执行不记录
+	if n := attempts.Load(); n == 0 || n > 2 {
+		t.Fatalf("failing article should be retried with backoff, got %d attempts", n)
+	}
+	var runs []models.SchedulerRun
+	e.db.Where("name = ?", "expire_due").Find(&runs)
+	if len(runs) != 1 || runs[0].Items != 1 {
+		t.Fatalf("only the run that expired an article should be recorded, got %+v", runs)
+	}
+}
@@ -0,0 +1,219 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"static-hosting-server/internal/app"
+	"static-hosting-server/internal/config"
+	"static-hosting-server/internal/database"
+	"static-hosting-server/internal/models"
+	"static-hosting-server/internal/web"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// 测试使用的静态API密钥
+const testAPIKey = "test-api-key"
+
+// 测试环境：内存数据库、临时静态目录和完整的路由
+type testEnv struct {
+	t      *testing.T
+	db     *gorm.DB
+	cfg    *config.Config
+	app    *app.App
+	router *gin.Engine
+}
+
+// 测试中解析的响应
+type testResponse struct {
+	Code   int
+	Header http.Header
+	Body   string
+	N8nResponse
+	RawData json.RawMessage
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	// 每个测试使用独立的内存数据库
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", name)), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	database.DB = db
+	if err := database.Migrate(); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	cfg := &config.Config{
+		Server:   config.ServerConfig{Domain: "blog.example.test", Mode: "release"},
+		Security: config.SecurityConfig{JWTSecret: "test-secret", APIKeys: []string{testAPIKey}},
+		Storage:  config.StorageConfig{StaticPath: t.TempDir(), UploadsPath: t.TempDir()},
+		Expiry:   config.ExpiryConfig{DeleteGracePeriod: time.Hour},
+	}
+
+	application := app.New(cfg, db)
+	router := gin.New()
+	if err := application.Themes.LoadAdminTemplates(router); err != nil {
+		t.Fatalf("load templates: %v", err)
+	}
+	SetupRoutes(router, application)
+	web.SetupRoutes(router, application)
+
+	return &testEnv{t: t, db: db, cfg: cfg, app: application, router: router}
+}
+
+// 发送请求，body不为空时按JSON编码
+func (e *testEnv) request(method, path, apiKey string, body interface{}) *testResponse {
+	e.t.Helper()
+	return e.requestWithHeader(method, path, apiKey, nil, body)
+}
+
+// 发送带额外请求头的请求
+func (e *testEnv) requestWithHeader(method, path, apiKey string, header http.Header, body interface{}) *testResponse {
+	e.t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			e.t.Fatalf("encode body: %v", err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Host = e.cfg.Server.Domain
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, req)
+
+	resp := &testResponse{Code: w.Code, Header: w.Header(), Body: w.Body.String()}
+	if strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		var envelope struct {
+			N8nResponse
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+			e.t.Fatalf("%s %s: invalid JSON response: %v\n%s", method, path, err, resp.Body)
+		}
+		resp.N8nResponse = envelope.N8nResponse
+		resp.RawData = envelope.Data
+	}
+	return resp
+}
+
+// 以内置管理员身份提交后台表单
+func (e *testEnv) admin(method, path string, form url.Values) *testResponse {
+	e.t.Helper()
+
+	var reader io.Reader
+	if form != nil {
+		reader = strings.NewReader(form.Encode())
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Host = e.cfg.Server.Domain
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	return e.serveAdmin(req)
+}
+
+// 带管理员会话发送请求，JSON响应解析到 RawData
+func (e *testEnv) serveAdmin(req *http.Request) *testResponse {
+	e.t.Helper()
+	req.AddCookie(&http.Cookie{Name: "admin_session", Value: e.app.Auth.GenerateSessionToken(&models.User{})})
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, req)
+
+	resp := &testResponse{Code: w.Code, Header: w.Header(), Body: w.Body.String()}
+	if strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		resp.RawData = w.Body.Bytes()
+	}
+	return resp
+}
+
+// 使用测试密钥调用API
+func (e *testEnv) api(method, path string, body interface{}) *testResponse {
+	e.t.Helper()
+	return e.request(method, path, testAPIKey, body)
+}
+
+// 检查状态码
+func (r *testResponse) expect(t *testing.T, code int) *testResponse {
+	t.Helper()
+	if r.Code != code {
+		t.Fatalf("expected status %d, got %d: %s", code, r.Code, r.Body)
+	}
+	return r
+}
+
+// 解析 data 字段
+func (r *testResponse) decode(t *testing.T, out interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(r.RawData, out); err != nil {
+		t.Fatalf("decode data: %v\n%s", err, r.Body)
+	}
+}
+
+// 通过API创建文章
+func (e *testEnv) createArticle(body map[string]interface{}) *models.Article {
+	e.t.Helper()
+	var article models.Article
+	e.api(http.MethodPost, "/api/articles", body).expect(e.t, http.StatusCreated).decode(e.t, &article)
+	return &article
+}
+
+// 执行队列中的后台任务（测试中不启动工作协程）
+func (e *testEnv) runJobs() {
+	e.t.Helper()
+	if _, err := e.app.Jobs.RunDue(); err != nil {
+		e.t.Fatalf("run jobs: %v", err)
+	}
+}
+
+// 默认站点文章的静态文件路径
+func (e *testEnv) staticFile(slug string) string {
+	return filepath.Join(e.cfg.Storage.StaticPath, "articles", slug, "index.html")
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
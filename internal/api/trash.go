@@ -0,0 +1,83 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 获取回收站中的文章，按删除时间倒序
+func (h *Handler) ListTrash(c *gin.Context) {
+	limit, err := parseLimit(c)
+	if err != nil {
+		respondFailure(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		respondFailure(c, http.StatusBadRequest, "page must be a positive integer")
+		return
+	}
+
+	articles, total, err := h.articles(c).ListTrash(page, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+		Data: gin.H{
+			"articles": articles,
+			"total":    total,
+			"page":     page,
+			"limit":    limit,
+		},
+	})
+}
+
+// 从回收站恢复文章并重新生成页面，原slug已被其他文章使用时返回409，可以通过 slug 指定新的slug
+func (h *Handler) RestoreArticle(c *gin.Context) {
+	var req struct {
+		Slug string `json:"slug"`
+	}
+
+	// 请求体可以为空，使用原slug
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondFailure(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	article, err := h.articles(c).RestoreArticle(c.Param("id"), req.Slug)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	publishURL := ""
+	if article.Status == "published" {
+		publishURL = h.articleService.PublicURL(article)
+	}
+
+	setArticleETag(c, article)
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+		Data:    article,
+		URL:     publishURL,
+	})
+}
+
+// 彻底删除回收站中的文章
+func (h *Handler) PurgeArticle(c *gin.Context) {
+	if err := h.articles(c).PurgeArticle(c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, N8nResponse{
+		Success: true,
+	})
+}
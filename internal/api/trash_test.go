@@ -0,0 +1,119 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"static-hosting-server/internal/models"
+	"static-hosting-server/internal/services"
+)
+
+func TestTrashRestoreAndPurge(t *testing.T) {
+	e := newTestEnv(t)
+	e.cfg.Trash.Retention = 30 * 24 * time.Hour
+	created := e.createArticle(map[string]interface{}{"title": "Promo", "content": "<p>1</p>", "slug": "promo", "status": "published"})
+	e.runJobs()
+
+	e.api(http.MethodDelete, "/api/articles/"+created.ID, nil).expect(t, http.StatusOK)
+	e.runJobs()
+	if fileExists(e.staticFile("promo")) {
+		t.Fatal("deleted article should be taken offline")
+	}
+	e.api(http.MethodGet, "/api/articles/"+created.ID, nil).expect(t, http.StatusNotFound)
+
+	var trash struct {
+		Articles []services.TrashedArticle `json:"articles"`
+		Total    int64                     `json:"total"`
+	}
+	e.api(http.MethodGet, "/api/trash", nil).expect(t, http.StatusOK).decode(t, &trash)
+	if trash.Total != 1 || trash.Articles[0].ID != created.ID || trash.Articles[0].PurgeAt == nil {
+		t.Fatalf("deleted article should be listed in the trash, got %+v", trash)
+	}
+
+	// 回收站中的文章不占用slug
+	replacement := e.createArticle(map[string]interface{}{"title": "New promo", "content": "<p>2</p>", "slug": "promo", "status": "published"})
+	if replacement.Slug != "promo" {
+		t.Fatalf("slug of a trashed article should be reusable, got %s", replacement.Slug)
+	}
+
+	restore := "/api/trash/" + created.ID + "/restore"
+	e.api(http.MethodPost, restore, nil).expect(t, http.StatusConflict)
+	e.api(http.MethodPost, restore, map[string]interface{}{"slug": "Bad Slug!"}).expect(t, http.StatusUnprocessableEntity)
+
+	var article models.Article
+	resp := e.api(http.MethodPost, restore, map[string]interface{}{"slug": "promo-2023"}).expect(t, http.StatusOK)
+	resp.decode(t, &article)
+	if article.Slug != "promo-2023" || article.Status != "published" || resp.URL == "" {
+		t.Fatalf("article should be restored under the new slug, got %s %s %q", article.Slug, article.Status, resp.URL)
+	}
+	if article.Version != created.Version+1 {
+		t.Fatalf("restore should bump the version, got %d", article.Version)
+	}
+	e.runJobs()
+	if !fileExists(e.staticFile("promo-2023")) || !fileExists(e.staticFile("promo")) {
+		t.Fatal("restored and replacement articles should both be rendered")
+	}
+	e.api(http.MethodPost, restore, nil).expect(t, http.StatusNotFound)
+
+	// 彻底删除后无法恢复
+	e.api(http.MethodDelete, "/api/articles/"+replacement.ID, nil).expect(t, http.StatusOK)
+	e.api(http.MethodDelete, "/api/trash/"+replacement.ID, nil).expect(t, http.StatusOK)
+	e.api(http.MethodDelete, "/api/trash/"+replacement.ID, nil).expect(t, http.StatusNotFound)
+	e.api(http.MethodPost, "/api/trash/"+replacement.ID+"/restore", nil).expect(t, http.StatusNotFound)
+	var count int64
+	e.db.Unscoped().Model(&models.Article{}).Where("id = ?", replacement.ID).Count(&count)
+	if count != 0 {
+		t.Fatal("purged article should be removed from the database")
+	}
+}
+
+func TestPurgeTrashRetention(t *testing.T) {
+	e := newTestEnv(t)
+	old := e.createArticle(map[string]interface{}{"title": "Old", "content": "<p>1</p>"})
+	recent := e.createArticle(map[string]interface{}{"title": "Recent", "content": "<p>2</p>"})
+	for _, id := range []string{old.ID, recent.ID} {
+		e.api(http.MethodDelete, "/api/articles/"+id, nil).expect(t, http.StatusOK)
+	}
+	e.db.Unscoped().Model(&models.Article{}).Where("id = ?", old.ID).Update("deleted_at", time.Now().Add(-48*time.Hour))
+
+	// 未配置保留期时不自动删除
+	if purged, err := e.app.Articles.PurgeTrash(); err != nil || purged != 0 {
+		t.Fatalf("trash should be kept without retention, got %d %v", purged, err)
+	}
+
+	e.cfg.Trash.Retention = 24 * time.Hour
+	if purged, err := e.app.Articles.PurgeTrash(); err != nil || purged != 1 {
+		t.Fatalf("one article should be purged, got %d %v", purged, err)
+	}
+	if _, err := e.app.Articles.GetTrashedArticle(old.ID); err == nil {
+		t.Fatal("article past retention should be purged")
+	}
+	if _, err := e.app.Articles.GetTrashedArticle(recent.ID); err != nil {
+		t.Fatalf("recently deleted article should stay in the trash: %v", err)
+	}
+}
+
+func TestTrashAdmin(t *testing.T) {
+	e := newTestEnv(t)
+	created := e.createArticle(map[string]interface{}{"title": "Trashed", "content": "<p>1</p>", "slug": "trashed", "status": "published"})
+	e.admin(http.MethodPost, "/admin/articles/"+created.ID+"/delete", url.Values{}).expect(t, http.StatusFound)
+
+	page := e.admin(http.MethodGet, "/admin/trash", nil).expect(t, http.StatusOK)
+	if !strings.Contains(page.Body, "/admin/trash/"+created.ID+"/restore") {
+		t.Fatal("trash page should list the deleted article")
+	}
+
+	e.createArticle(map[string]interface{}{"title": "Taken", "content": "<p>2</p>", "slug": "trashed"})
+	e.admin(http.MethodPost, "/admin/trash/"+created.ID+"/restore", url.Values{}).expect(t, http.StatusBadRequest)
+	e.admin(http.MethodPost, "/admin/trash/"+created.ID+"/restore", url.Values{"slug": {"trashed-again"}}).expect(t, http.StatusFound)
+	if article, err := e.app.Articles.GetArticleByID(created.ID); err != nil || article.Slug != "trashed-again" {
+		t.Fatalf("article should be restored with the new slug, got %v %v", article, err)
+	}
+
+	e.admin(http.MethodPost, "/admin/articles/"+created.ID+"/delete", url.Values{}).expect(t, http.StatusFound)
+	e.admin(http.MethodPost, "/admin/trash/"+created.ID+"/purge", url.Values{}).expect(t, http.StatusFound)
+	e.admin(http.MethodPost, "/admin/trash/"+created.ID+"/purge", url.Values{}).expect(t, http.StatusNotFound)
+}
@@ -0,0 +1,37 @@
+// Package app 组装应用的依赖，main 中创建一次后传给API、后台路由和定时任务。
+package app
+
+import (
+	"static-hosting-server/internal/auth"
+	"static-hosting-server/internal/config"
+	"static-hosting-server/internal/events"
+	"static-hosting-server/internal/services"
+	"static-hosting-server/internal/theme"
+
+	"gorm.io/gorm"
+)
+
+// App 应用容器，测试中可以在创建路由前替换其中的实现
+type App struct {
+	Config *config.Config
+	DB     *gorm.DB
+	Events *events.Bus
+	Themes *theme.Manager
+	Auth   *auth.AuthService
+
+	*services.Services
+}
+
+func New(cfg *config.Config, db *gorm.DB) *App {
+	bus := events.NewBus()
+	themes := theme.NewManager(cfg)
+
+	return &App{
+		Config:   cfg,
+		DB:       db,
+		Events:   bus,
+		Themes:   themes,
+		Auth:     auth.NewAuthService(db, cfg),
+		Services: services.NewServices(db, cfg, themes, bus),
+	}
+}
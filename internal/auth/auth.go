@@ -1,15 +1,27 @@
 package auth
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
 	"net/http"
 	"static-hosting-server/internal/config"
 	"static-hosting-server/internal/models"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
+// 管理员会话有效期
+const sessionTTL = 7 * 24 * time.Hour
+
 type AuthService struct {
 	db  *gorm.DB
 	cfg *config.Config
@@ -34,6 +46,7 @@ func (a *AuthService) APIKeyAuthMiddleware() gin.HandlerFunc {
 		if apiKey == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"success": false,
+				"code":    "unauthorized",
 				"error":   "API key required",
 			})
 			c.Abort()
@@ -42,11 +55,13 @@ func (a *AuthService) APIKeyAuthMiddleware() gin.HandlerFunc {
 
 		// 验证API密钥
 		var dbAPIKey models.APIKey
-		if err := a.db.Where("key = ? AND is_active = ?", apiKey, true).First(&dbAPIKey).Error; err != nil {
+		// 使用结构体条件，由GORM为保留字 key 加引号
+		if err := a.db.Where(&models.APIKey{Key: apiKey, IsActive: true}).First(&dbAPIKey).Error; err != nil {
 			// 检查配置中的静态API密钥
 			if !a.isStaticAPIKey(apiKey) {
 				c.JSON(http.StatusUnauthorized, gin.H{
 					"success": false,
+					"code":    "unauthorized",
 					"error":   "Invalid or inactive API key",
 				})
 				c.Abort()
@@ -57,6 +72,7 @@ func (a *AuthService) APIKeyAuthMiddleware() gin.HandlerFunc {
 			if dbAPIKey.ExpiresAt != nil && dbAPIKey.ExpiresAt.Before(time.Now()) {
 				c.JSON(http.StatusUnauthorized, gin.H{
 					"success": false,
+					"code":    "unauthorized",
 					"error":   "API key has expired",
 				})
 				c.Abort()
@@ -103,8 +119,9 @@ func (a *AuthService) AdminAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// 验证会话token（这里可以实现更复杂的会话验证）
-		if !a.validateSessionToken(sessionToken) {
+		// 验证会话token并加载管理员
+		user, ok := a.validateSessionToken(sessionToken)
+		if !ok {
 			// 清除无效cookie
 			c.SetCookie("admin_session", "", -1, "/", "", false, true)
 
@@ -118,32 +135,133 @@ func (a *AuthService) AdminAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// 将管理员信息存储在上下文中
+		c.Set("admin_user", user)
+
 		c.Next()
 	}
 }
 
-// 验证会话token
-func (a *AuthService) validateSessionToken(token string) bool {
-	// 简单的token验证，实际项目中可以使用更安全的方式
-	// 这里可以检查数据库中的会话记录、过期时间等
-	return token == "valid_admin_session_2025"
+// 验证会话token，格式为 用户ID.过期时间.签名
+func (a *AuthService) validateSessionToken(token string) (*models.User, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, false
+	}
+
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(a.sign(payload))) {
+		return nil, false
+	}
+
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return nil, false
+	}
+
+	userID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	if userID == 0 {
+		return builtinAdmin(), true
+	}
+
+	var user models.User
+	if err := a.db.Where("id = ? AND role = ?", userID, "admin").First(&user).Error; err != nil {
+		return nil, false
+	}
+	return &user, true
 }
 
 // 生成会话token
-func (a *AuthService) GenerateSessionToken() string {
-	// 实际项目中应该生成随机token并存储到数据库
-	return "valid_admin_session_2025"
+func (a *AuthService) GenerateSessionToken(user *models.User) string {
+	payload := fmt.Sprintf("%d.%d", user.ID, time.Now().Add(sessionTTL).Unix())
+	return payload + "." + a.sign(payload)
+}
+
+// 使用JWT密钥对数据签名
+func (a *AuthService) sign(payload string) string {
+	mac := hmac.New(sha256.New, []byte(a.cfg.Security.JWTSecret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// 验证管理员登录凭据，优先使用数据库中的管理员账号
+func (a *AuthService) AuthenticateAdmin(username, password string) (*models.User, bool) {
+	var user models.User
+	if err := a.db.Where("username = ? AND role = ?", username, "admin").First(&user).Error; err == nil {
+		if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+			return nil, false
+		}
+		return &user, true
+	}
+
+	// 内置管理员账号，可管理所有站点
+	if username == "admin" && password == "admin123" {
+		return builtinAdmin(), true
+	}
+	return nil, false
+}
+
+// 内置的超级管理员
+func builtinAdmin() *models.User {
+	return &models.User{
+		Username: "admin",
+		Role:     "admin",
+	}
+}
+
+// 生成bcrypt密码哈希
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// 当前请求可访问的站点：API密钥或管理员绑定了站点时返回该站点，否则返回nil表示不限制
+func SiteScope(c *gin.Context) *uint {
+	if value, ok := c.Get("api_key"); ok {
+		if apiKey, ok := value.(*models.APIKey); ok {
+			return apiKey.SiteID
+		}
+	}
+	if value, ok := c.Get("admin_user"); ok {
+		if user, ok := value.(*models.User); ok {
+			return user.SiteID
+		}
+	}
+	return nil
+}
+
+// 当前请求使用的数据库中的API密钥，使用配置中的静态密钥或未通过API密钥认证时返回nil
+func APIKey(c *gin.Context) *models.APIKey {
+	if value, ok := c.Get("api_key"); ok {
+		if apiKey, ok := value.(*models.APIKey); ok {
+			return apiKey
+		}
+	}
+	return nil
 }
 
-// 验证管理员登录凭据
-func (a *AuthService) ValidateAdminCredentials(username, password string) bool {
-	// 简化的验证逻辑，实际项目中应该查询数据库
-	// 并使用bcrypt等方式验证密码
-	return username == "admin" && password == "admin123"
+// 当前登录的管理员，未登录时返回nil；内置管理员的ID为0
+func AdminUser(c *gin.Context) *models.User {
+	if value, ok := c.Get("admin_user"); ok {
+		if user, ok := value.(*models.User); ok {
+			return user
+		}
+	}
+	return nil
 }
 
-// 生成新的API密钥
-func (a *AuthService) GenerateAPIKey(name string, permissions string, expiresAt *time.Time) (*models.APIKey, error) {
+// 生成新的API密钥，siteID不为空时密钥只能访问该站点；defaultTTLHours 为通过此密钥创建的文章的默认有效期，0 表示使用站点设置
+func (a *AuthService) GenerateAPIKey(name string, permissions string, expiresAt *time.Time, siteID *uint, defaultTTLHours int) (*models.APIKey, error) {
+	if defaultTTLHours < 0 {
+		return nil, fmt.Errorf("default TTL must not be negative")
+	}
+
 	// 生成随机密钥
 	key := generateRandomKey(32)
 
@@ -153,6 +271,9 @@ func (a *AuthService) GenerateAPIKey(name string, permissions string, expiresAt
 		IsActive:    true,
 		Permissions: permissions,
 		ExpiresAt:   expiresAt,
+		SiteID:      siteID,
+
+		DefaultTTLHours: defaultTTLHours,
 	}
 
 	if err := a.db.Create(apiKey).Error; err != nil {
@@ -165,13 +286,49 @@ func (a *AuthService) GenerateAPIKey(name string, permissions string, expiresAt
 // 生成随机密钥
 func generateRandomKey(length int) string {
 	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	max := big.NewInt(int64(len(charset)))
 	result := make([]byte, length)
-	// 使用更好的随机种子
-	timeNano := time.Now().UnixNano()
 	for i := range result {
-		// 使用不同的种子避免相同的字符
-		timeNano = timeNano*1103515245 + 12345 // 线性同余生成器
-		result[i] = charset[timeNano%int64(len(charset))]
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(fmt.Sprintf("crypto/rand unavailable: %v", err))
+		}
+		result[i] = charset[n.Int64()]
 	}
 	return string(result)
 }
+
+// 获取API密钥列表，siteID不为空时只返回该站点的密钥
+func (a *AuthService) ListAPIKeys(siteID *uint) ([]models.APIKey, error) {
+	query := a.db.Order("id")
+	if siteID != nil {
+		query = query.Where("site_id = ?", *siteID)
+	}
+
+	var keys []models.APIKey
+	if err := query.Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// 根据ID获取API密钥
+func (a *AuthService) GetAPIKeyByID(id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := a.db.First(&key, id).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// 停用API密钥，停用后的密钥无法再通过认证
+func (a *AuthService) RevokeAPIKey(id uint) error {
+	result := a.db.Model(&models.APIKey{}).Where("id = ?", id).Update("is_active", false)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
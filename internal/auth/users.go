@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"fmt"
+	"static-hosting-server/internal/models"
+)
+
+// 管理员密码最短长度
+const minPasswordLength = 8
+
+// 创建管理员账号，siteID不为空时只能管理该站点
+func (a *AuthService) CreateAdmin(username, email, password string, siteID *uint) (*models.User, error) {
+	if username == "" || email == "" {
+		return nil, fmt.Errorf("username and email are required")
+	}
+	hash, err := hashAdminPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing models.User
+	if err := a.db.Where("username = ? OR email = ?", username, email).First(&existing).Error; err == nil {
+		return nil, fmt.Errorf("user '%s' or email '%s' already exists", username, email)
+	}
+
+	user := &models.User{
+		Username: username,
+		Email:    email,
+		Password: hash,
+		Role:     "admin",
+		SiteID:   siteID,
+	}
+	if err := a.db.Create(user).Error; err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// 重置用户密码
+func (a *AuthService) SetPassword(username, password string) error {
+	hash, err := hashAdminPassword(password)
+	if err != nil {
+		return err
+	}
+
+	result := a.db.Model(&models.User{}).Where("username = ?", username).Update("password", hash)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user '%s' not found", username)
+	}
+	return nil
+}
+
+// 获取用户列表
+func (a *AuthService) ListUsers() ([]models.User, error) {
+	var users []models.User
+	if err := a.db.Order("id").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// 校验密码长度并生成哈希
+func hashAdminPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+	return HashPassword(password)
+}
@@ -1,21 +1,31 @@
 package config
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	ACME     ACMEConfig     `mapstructure:"acme"`
-	Security SecurityConfig `mapstructure:"security"`
-	Storage  StorageConfig  `mapstructure:"storage"`
+	Server    ServerConfig    `mapstructure:"server"`
+	Database  DatabaseConfig  `mapstructure:"database"`
+	ACME      ACMEConfig      `mapstructure:"acme"`
+	Security  SecurityConfig  `mapstructure:"security"`
+	Storage   StorageConfig   `mapstructure:"storage"`
+	Theme     ThemeConfig     `mapstructure:"theme"`
+	Expiry    ExpiryConfig    `mapstructure:"expiry"`
+	Notify    NotifyConfig    `mapstructure:"notify"`
+	Slug      SlugConfig      `mapstructure:"slug"`
+	Jobs      JobsConfig      `mapstructure:"jobs"`
+	Scheduler SchedulerConfig `mapstructure:"scheduler"`
+	Trash     TrashConfig     `mapstructure:"trash"`
 }
 
 type ServerConfig struct {
-	Port   string `mapstructure:"port"`
-	Mode   string `mapstructure:"mode"`
-	Domain string `mapstructure:"domain"`
+	Port      string `mapstructure:"port"`
+	HTTPSPort string `mapstructure:"https_port"` // 为空时不启用HTTPS
+	Mode      string `mapstructure:"mode"`
+	Domain    string `mapstructure:"domain"`
 }
 
 type DatabaseConfig struct {
@@ -43,6 +53,52 @@ type StorageConfig struct {
 	CertsPath   string `mapstructure:"certs_path"`
 }
 
+type ThemeConfig struct {
+	Path    string `mapstructure:"path"`    // 主题目录，每个子目录为一个主题
+	Default string `mapstructure:"default"` // 未指定主题时使用，default 为内置主题
+}
+
+type ExpiryConfig struct {
+	WarningDays       int           `mapstructure:"warning_days"`        // 提前多少天发送过期提醒，0 表示不提醒
+	DeleteGracePeriod time.Duration `mapstructure:"delete_grace_period"` // 过期处理为 delete 时，过期多久后删除数据
+	DefaultTTL        time.Duration `mapstructure:"default_ttl"`         // 通过接口创建的文章未指定过期时间时的有效期，0 表示不过期；API密钥和站点的设置优先
+}
+
+type NotifyConfig struct {
+	WebhookURL string     `mapstructure:"webhook_url"`
+	SMTP       SMTPConfig `mapstructure:"smtp"`
+}
+
+type SMTPConfig struct {
+	Host     string   `mapstructure:"host"` // 为空时不发送邮件
+	Port     int      `mapstructure:"port"`
+	Username string   `mapstructure:"username"`
+	Password string   `mapstructure:"password"`
+	From     string   `mapstructure:"from"`
+	To       []string `mapstructure:"to"`
+}
+
+type SlugConfig struct {
+	Strategy string `mapstructure:"strategy"` // 从标题生成slug的方式：pinyin（默认）, unicode, ascii
+}
+
+type JobsConfig struct {
+	Workers      int           `mapstructure:"workers"`       // 工作协程数量，默认 2
+	PollInterval time.Duration `mapstructure:"poll_interval"` // 没有任务时的轮询间隔，默认 2s
+	MaxAttempts  int           `mapstructure:"max_attempts"`  // 最多执行次数，超过后进入失败列表，默认 5
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"` // 首次重试的等待时间，之后每次翻倍，默认 10s
+	LockTimeout  time.Duration `mapstructure:"lock_timeout"`  // 执行超过此时间的任务视为中断并重新排队，默认 10m
+}
+
+type SchedulerConfig struct {
+	LeaseTTL  time.Duration     `mapstructure:"lease_ttl"` // 主节点租约时长，主节点退出后其他节点最多等待此时间接管，默认 30s
+	Schedules map[string]string `mapstructure:"schedules"` // 任务名称到cron表达式（含秒），off 表示不定时执行，未配置的任务使用默认值
+}
+
+type TrashConfig struct {
+	Retention time.Duration `mapstructure:"retention"` // 删除的文章在回收站中保留多久后由 purge_trash 任务彻底删除，0 表示不自动删除
+}
+
 func Load() (*Config, error) {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
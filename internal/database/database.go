@@ -14,7 +14,23 @@ import (
 
 var DB *gorm.DB
 
+// 连接数据库并自动迁移
 func Initialize(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	if _, err := Connect(cfg, logger.Info); err != nil {
+		return nil, err
+	}
+
+	// 自动迁移数据库
+	if err := Migrate(); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	log.Println("Database connected and migrated successfully")
+	return DB, nil
+}
+
+// 连接数据库，不执行迁移
+func Connect(cfg config.DatabaseConfig, logLevel logger.LogLevel) (*gorm.DB, error) {
 	// 构建DSN连接字符串，添加超时参数
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=True&loc=Local&timeout=30s&readTimeout=30s&writeTimeout=30s",
 		cfg.User,
@@ -33,7 +49,7 @@ func Initialize(cfg config.DatabaseConfig) (*gorm.DB, error) {
 	// 重试连接机制
 	for i := 0; i < retries; i++ {
 		DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
-			Logger: logger.Default.LogMode(logger.Info),
+			Logger: logger.Default.LogMode(logLevel),
 		})
 
 		if err == nil {
@@ -62,23 +78,62 @@ func Initialize(cfg config.DatabaseConfig) (*gorm.DB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database after %d attempts: %w", retries, err)
 	}
-
-	// 自动迁移数据库
-	if err := autoMigrate(); err != nil {
-		return nil, fmt.Errorf("failed to migrate database: %w", err)
-	}
-
-	log.Println("Database connected and migrated successfully")
 	return DB, nil
 }
 
-func autoMigrate() error {
-	return DB.AutoMigrate(
+// 自动迁移表结构
+func Migrate() error {
+	if err := migrateLegacySchema(); err != nil {
+		return err
+	}
+
+	if err := DB.AutoMigrate(
 		&models.Article{},
 		&models.User{},
 		&models.APIKey{},
 		&models.Certificate{},
-	)
+		&models.Domain{},
+		&models.Site{},
+		&models.PreviewToken{},
+		&models.Redirect{},
+		&models.Blueprint{},
+		&models.Job{},
+		&models.SchedulerLease{},
+		&models.SchedulerRun{},
+		&models.ArticleDraft{},
+	); err != nil {
+		return err
+	}
+
+	// 增加 trash_key 之前删除的文章
+	return DB.Unscoped().Model(&models.Article{}).
+		Where("deleted_at IS NOT NULL AND trash_key = ?", "").
+		Update("trash_key", gorm.Expr("id")).Error
+}
+
+// 自动迁移前调整旧版表结构
+func migrateLegacySchema() error {
+	migrator := DB.Migrator()
+	if !migrator.HasTable("articles") {
+		return nil
+	}
+
+	// 文章slug由全局唯一改为站点内唯一
+	for _, name := range []string{"uni_articles_slug", "slug"} {
+		if migrator.HasIndex("articles", name) {
+			if err := migrator.DropIndex("articles", name); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 站点内slug唯一索引增加 trash_key 列，删除的文章不再占用slug，由自动迁移重新创建
+	if !migrator.HasColumn("articles", "trash_key") && migrator.HasIndex("articles", "idx_articles_site_slug") {
+		if err := migrator.DropIndex("articles", "idx_articles_site_slug"); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func GetDB() *gorm.DB {
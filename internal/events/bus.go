@@ -0,0 +1,75 @@
+// Package events 提供进程内的事件总线，服务在数据变化后发布事件，缓存、通知等功能通过订阅接入。
+package events
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// 文章事件
+const (
+	ArticleCreated   = "article.created"
+	ArticleUpdated   = "article.updated"
+	ArticlePublished = "article.published" // 文章从其他状态变为已发布
+	ArticleDeleted   = "article.deleted"   // 移入回收站
+	ArticleRestored  = "article.restored"  // 从回收站恢复
+	ArticleExpired   = "article.expired"
+)
+
+// 订阅所有事件
+const All = "*"
+
+// Event 事件
+type Event struct {
+	Name      string
+	SiteID    uint
+	SubjectID string      // 相关对象的ID，如文章ID
+	Data      interface{} // 相关对象，如 *models.Article
+	Time      time.Time
+}
+
+// Handler 事件处理函数，在发布事件的协程中同步执行，耗时操作应自行异步处理
+type Handler func(Event)
+
+// Bus 事件总线，nil 时发布事件为空操作
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[string][]Handler)}
+}
+
+// 订阅事件，name 为 All 时接收所有事件
+func (b *Bus) Subscribe(name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[name] = append(b.handlers[name], handler)
+}
+
+// 发布事件，处理函数的panic会被记录而不影响发布方
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	handlers := append(append([]Handler(nil), b.handlers[event.Name]...), b.handlers[All]...)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("Event handler for %s panicked: %v", event.Name, r)
+				}
+			}()
+			handler(event)
+		}()
+	}
+}
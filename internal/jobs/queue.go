@@ -0,0 +1,337 @@
+// Package jobs 基于数据库的后台任务队列。任务与业务数据在同一事务中写入，
+// 由工作协程取出执行，失败后按指数退避重试，超过次数的任务保留在失败列表中等待手动重试。
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"static-hosting-server/internal/config"
+	"static-hosting-server/internal/models"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 任务状态
+const (
+	StatusPending = "pending" // 等待执行，包括等待重试的任务
+	StatusRunning = "running"
+	StatusDone    = "done"
+	StatusDead    = "dead" // 超过最多执行次数，需要手动重试
+)
+
+// 已完成任务的保留时间
+const doneRetention = 7 * 24 * time.Hour
+
+// 退避时间上限
+const maxBackoff = time.Hour
+
+// Handler 执行一种任务，返回错误时任务稍后重试
+type Handler func(payload []byte) error
+
+type Queue struct {
+	db  *gorm.DB
+	cfg config.JobsConfig
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewQueue(db *gorm.DB, cfg *config.Config) *Queue {
+	jobsCfg := cfg.Jobs
+	if jobsCfg.Workers <= 0 {
+		jobsCfg.Workers = 2
+	}
+	if jobsCfg.PollInterval <= 0 {
+		jobsCfg.PollInterval = 2 * time.Second
+	}
+	if jobsCfg.MaxAttempts <= 0 {
+		jobsCfg.MaxAttempts = 5
+	}
+	if jobsCfg.RetryBackoff <= 0 {
+		jobsCfg.RetryBackoff = 10 * time.Second
+	}
+	if jobsCfg.LockTimeout <= 0 {
+		jobsCfg.LockTimeout = 10 * time.Minute
+	}
+
+	return &Queue{
+		db:       db,
+		cfg:      jobsCfg,
+		handlers: make(map[string]Handler),
+	}
+}
+
+// 注册任务类型的处理函数
+func (q *Queue) Register(jobType string, handler Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[jobType] = handler
+}
+
+// 在tx中添加任务，tx提交后任务才会被执行，tx为空时直接添加；payload按JSON编码
+func (q *Queue) Enqueue(tx *gorm.DB, jobType string, siteID uint, payload interface{}) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode job payload: %w", err)
+	}
+	if tx == nil {
+		tx = q.db
+	}
+
+	return tx.Create(&models.Job{
+		Type:        jobType,
+		Payload:     string(raw),
+		SiteID:      siteID,
+		Status:      StatusPending,
+		MaxAttempts: q.cfg.MaxAttempts,
+		RunAt:       time.Now(),
+	}).Error
+}
+
+// 启动工作协程
+func (q *Queue) Start() {
+	if q.stop != nil {
+		return
+	}
+	q.stop = make(chan struct{})
+
+	for i := 0; i < q.cfg.Workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+
+	q.wg.Add(1)
+	go q.maintain()
+
+	log.Printf("Job queue started with %d workers", q.cfg.Workers)
+}
+
+// 停止工作协程，等待正在执行的任务完成
+func (q *Queue) Stop() {
+	if q.stop == nil {
+		return
+	}
+	close(q.stop)
+	q.wg.Wait()
+	q.stop = nil
+	log.Println("Job queue stopped")
+}
+
+// 执行所有已到期的任务直到队列为空，返回执行的任务数量。供测试和命令行工具在没有工作协程时使用
+func (q *Queue) RunDue() (int, error) {
+	count := 0
+	for {
+		ran, err := q.runNext()
+		if err != nil {
+			return count, err
+		}
+		if !ran {
+			return count, nil
+		}
+		count++
+	}
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+
+	for {
+		ran, err := q.runNext()
+		if err != nil {
+			log.Printf("Failed to fetch job: %v", err)
+		}
+		if ran {
+			// 继续执行下一个任务，除非已经停止
+			select {
+			case <-q.stop:
+				return
+			default:
+			}
+			continue
+		}
+
+		select {
+		case <-q.stop:
+			return
+		case <-time.After(q.cfg.PollInterval):
+		}
+	}
+}
+
+// 定期回收中断的任务并清理已完成的任务
+func (q *Queue) maintain() {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		if err := q.requeueStale(); err != nil {
+			log.Printf("Failed to requeue stale jobs: %v", err)
+		}
+		if err := q.pruneDone(); err != nil {
+			log.Printf("Failed to prune finished jobs: %v", err)
+		}
+
+		select {
+		case <-q.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// 取出并执行一个到期的任务，队列中没有到期任务时返回 false
+func (q *Queue) runNext() (bool, error) {
+	for {
+		var job models.Job
+		err := q.db.Where("status = ? AND run_at <= ?", StatusPending, time.Now()).
+			Order("run_at, id").First(&job).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		// 多个工作协程或多个进程可能取到同一个任务，只有更新成功的一方执行
+		now := time.Now()
+		result := q.db.Model(&models.Job{}).Where("id = ? AND status = ?", job.ID, StatusPending).Updates(map[string]interface{}{
+			"status":    StatusRunning,
+			"locked_at": now,
+			"attempts":  gorm.Expr("attempts + 1"),
+		})
+		if result.Error != nil {
+			return false, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		job.Attempts++
+		q.finish(&job, q.execute(&job))
+		return true, nil
+	}
+}
+
+// 执行任务，处理函数panic时视为失败
+func (q *Queue) execute(job *models.Job) (err error) {
+	q.mu.RLock()
+	handler, ok := q.handlers[job.Type]
+	q.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no handler for job type '%s'", job.Type)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return handler([]byte(job.Payload))
+}
+
+// 记录执行结果：成功时标记完成，失败时安排重试或移入失败列表
+func (q *Queue) finish(job *models.Job, runErr error) {
+	now := time.Now()
+	updates := map[string]interface{}{"locked_at": nil}
+
+	switch {
+	case runErr == nil:
+		updates["status"] = StatusDone
+		updates["last_error"] = ""
+		updates["finished_at"] = now
+	case job.Attempts >= job.MaxAttempts:
+		log.Printf("Job %d (%s) failed permanently after %d attempts: %v", job.ID, job.Type, job.Attempts, runErr)
+		updates["status"] = StatusDead
+		updates["last_error"] = runErr.Error()
+		updates["finished_at"] = now
+	default:
+		log.Printf("Job %d (%s) failed, attempt %d/%d: %v", job.ID, job.Type, job.Attempts, job.MaxAttempts, runErr)
+		updates["status"] = StatusPending
+		updates["last_error"] = runErr.Error()
+		updates["run_at"] = now.Add(q.backoff(job.Attempts))
+	}
+
+	if err := q.db.Model(&models.Job{}).Where("id = ?", job.ID).Updates(updates).Error; err != nil {
+		log.Printf("Failed to record result of job %d: %v", job.ID, err)
+	}
+}
+
+// 第n次失败后的等待时间，每次翻倍
+func (q *Queue) backoff(attempts int) time.Duration {
+	delay := q.cfg.RetryBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
+
+// 执行超时的任务（进程在执行中退出）重新排队
+func (q *Queue) requeueStale() error {
+	return q.db.Model(&models.Job{}).
+		Where("status = ? AND locked_at < ?", StatusRunning, time.Now().Add(-q.cfg.LockTimeout)).
+		Updates(map[string]interface{}{
+			"status":     StatusPending,
+			"locked_at":  nil,
+			"last_error": "interrupted",
+			"run_at":     time.Now(),
+		}).Error
+}
+
+func (q *Queue) pruneDone() error {
+	return q.db.Where("status = ? AND finished_at < ?", StatusDone, time.Now().Add(-doneRetention)).
+		Delete(&models.Job{}).Error
+}
+
+// 获取失败的任务：已进入失败列表的任务和正在等待重试的任务，siteID不为空时只返回该站点的任务
+func (q *Queue) ListFailed(siteID *uint) ([]models.Job, error) {
+	query := q.db.Where("status = ? OR (status = ? AND attempts > 0)", StatusDead, StatusPending)
+	if siteID != nil {
+		query = query.Where("site_id = ?", *siteID)
+	}
+
+	var jobs []models.Job
+	if err := query.Order("updated_at DESC").Limit(200).Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// 根据ID获取任务
+func (q *Queue) GetJob(id uint) (*models.Job, error) {
+	var job models.Job
+	if err := q.db.First(&job, id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// 立即重新执行失败的任务，并重新计算执行次数
+func (q *Queue) Retry(id uint) error {
+	result := q.db.Model(&models.Job{}).
+		Where("id = ? AND (status = ? OR (status = ? AND attempts > 0))", id, StatusDead, StatusPending).
+		Updates(map[string]interface{}{
+			"status":      StatusPending,
+			"attempts":    0,
+			"run_at":      time.Now(),
+			"finished_at": nil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -8,22 +8,41 @@ import (
 )
 
 type Article struct {
-	ID        string         `json:"id" gorm:"type:varchar(36);primaryKey"`
-	Title     string         `json:"title" gorm:"not null;size:255"`
-	Content   string         `json:"content" gorm:"type:longtext"`
-	Slug      string         `json:"slug" gorm:"unique;not null;size:255"`
-	Status    string         `json:"status" gorm:"default:'draft';size:20"`
-	ExpiresAt *time.Time     `json:"expires_at"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID           string         `json:"id" gorm:"type:varchar(36);primaryKey"`
+	Title        string         `json:"title" gorm:"not null;size:255"`
+	Content      string         `json:"content" gorm:"type:longtext"`
+	SiteID       uint           `json:"site_id" gorm:"not null;default:0;uniqueIndex:idx_articles_site_slug"` // 0 表示默认站点
+	Slug         string         `json:"slug" gorm:"not null;size:255;uniqueIndex:idx_articles_site_slug"`
+	TrashKey     string         `json:"-" gorm:"not null;default:'';size:36;uniqueIndex:idx_articles_site_slug"` // 删除后为文章ID，使回收站中的文章不占用slug
+	Status       string         `json:"status" gorm:"default:'draft';size:20"`
+	ExpiresAt    *time.Time     `json:"expires_at"`
+	DomainID     *uint          `json:"domain_id" gorm:"index"`                     // 为空时使用默认域名
+	Theme        string         `json:"theme" gorm:"size:100"`                      // 为空时使用站点或全局主题
+	Visibility   string         `json:"visibility" gorm:"default:'public';size:20"` // public 公开，unlisted 仅凭链接访问，password 需要密码
+	PasswordHash string         `json:"-" gorm:"size:255"`
+	ExpiryAction string         `json:"expiry_action" gorm:"default:'unpublish';size:20"` // 过期后的处理：unpublish, archive, redirect, delete
+	RedirectURL  string         `json:"redirect_url" gorm:"size:2048"`                    // 过期处理为 redirect 时的跳转地址
+	WarnedAt     *time.Time     `json:"warned_at"`                                        // 已发送过期提醒的时间
+	Description  string         `json:"description" gorm:"size:500"`                      // 摘要，用于 meta description 和分享卡片，为空时从内容截取
+	CanonicalURL string         `json:"canonical_url" gorm:"size:2048"`                   // 为空时使用文章的访问地址
+	CoverImage   string         `json:"cover_image" gorm:"size:2048"`                     // 分享卡片图片，可使用站内路径
+	Author       string         `json:"author" gorm:"size:100"`
+	OGTitle      string         `json:"og_title" gorm:"size:255"`          // 分享卡片标题，为空时使用文章标题
+	TwitterCard  string         `json:"twitter_card" gorm:"size:30"`       // summary 或 summary_large_image，为空时按是否有封面选择
+	Version      int            `json:"version" gorm:"not null;default:1"` // 每次修改加1，用于ETag和并发修改检查
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
-// BeforeCreate 在创建前自动生成UUID
+// BeforeCreate 在创建前自动生成UUID和初始版本
 func (a *Article) BeforeCreate(tx *gorm.DB) error {
 	if a.ID == "" {
 		a.ID = uuid.New().String()
 	}
+	if a.Version == 0 {
+		a.Version = 1
+	}
 	return nil
 }
 
@@ -33,22 +52,25 @@ type User struct {
 	Email     string         `json:"email" gorm:"unique;not null;size:255"`
 	Password  string         `json:"-" gorm:"not null"`
 	Role      string         `json:"role" gorm:"default:'user';size:20"`
+	SiteID    *uint          `json:"site_id" gorm:"index"` // 为空时可管理所有站点
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 type APIKey struct {
-	ID          uint           `json:"id" gorm:"primaryKey"`
-	Name        string         `json:"name" gorm:"not null;size:100"`
-	Key         string         `json:"key" gorm:"unique;not null;size:255"`
-	IsActive    bool           `json:"is_active" gorm:"default:true"`
-	LastUsedAt  *time.Time     `json:"last_used_at"`
-	ExpiresAt   *time.Time     `json:"expires_at"`
-	Permissions string         `json:"permissions" gorm:"type:text"` // JSON格式存储权限
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID              uint           `json:"id" gorm:"primaryKey"`
+	Name            string         `json:"name" gorm:"not null;size:100"`
+	Key             string         `json:"key" gorm:"unique;not null;size:255"`
+	IsActive        bool           `json:"is_active" gorm:"default:true"`
+	LastUsedAt      *time.Time     `json:"last_used_at"`
+	ExpiresAt       *time.Time     `json:"expires_at"`
+	Permissions     string         `json:"permissions" gorm:"type:text"`       // JSON格式存储权限
+	SiteID          *uint          `json:"site_id" gorm:"index"`               // 为空时可访问所有站点
+	DefaultTTLHours int            `json:"default_ttl_hours" gorm:"default:0"` // 通过此密钥创建的文章未指定过期时间时的有效期（小时），0 表示使用站点设置
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 type Certificate struct {
@@ -62,3 +84,112 @@ type Certificate struct {
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
+
+// Domain 自定义域名，将额外的主机名映射到部分文章
+type Domain struct {
+	ID            uint           `json:"id" gorm:"primaryKey"`
+	Host          string         `json:"host" gorm:"unique;not null;size:255"`
+	SiteID        *uint          `json:"site_id" gorm:"index"` // 绑定站点时，该站点的所有文章都通过此域名访问
+	CertificateID *uint          `json:"certificate_id"`
+	IsActive      bool           `json:"is_active" gorm:"default:true"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Site 站点，拥有各自的文章、API密钥和管理员
+type Site struct {
+	ID              uint           `json:"id" gorm:"primaryKey"`
+	Name            string         `json:"name" gorm:"unique;not null;size:100"`
+	Theme           string         `json:"theme" gorm:"size:100"`
+	StoragePrefix   string         `json:"storage_prefix" gorm:"size:100"`     // 静态文件目录前缀，为空时使用 site-<ID>
+	DefaultTTLHours int            `json:"default_ttl_hours" gorm:"default:0"` // 通过接口创建的文章未指定过期时间时的有效期（小时），0 表示使用全局配置
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// PreviewToken 草稿预览链接，签名校验通过且未撤销、未过期时可访问
+type PreviewToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	ArticleID string     `json:"article_id" gorm:"type:varchar(36);not null;index"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Redirect 路径跳转，用于文章改名或删除后保留旧链接
+type Redirect struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	SiteID     uint      `json:"site_id" gorm:"not null;default:0;uniqueIndex:idx_redirects_site_source"`
+	SourcePath string    `json:"source_path" gorm:"not null;size:512;uniqueIndex:idx_redirects_site_source"` // 如 /p/old-slug
+	Target     string    `json:"target" gorm:"not null;size:2048"`                                           // 站内路径或完整URL
+	StatusCode int       `json:"status_code" gorm:"default:301"`
+	HitCount   int64     `json:"hit_count" gorm:"default:0"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Blueprint 文章模板，标题和内容中可以使用 {{name}} 形式的占位符
+type Blueprint struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	SiteID       uint      `json:"site_id" gorm:"not null;default:0;uniqueIndex:idx_blueprints_site_name"` // 默认站点的模板所有站点可用
+	Name         string    `json:"name" gorm:"not null;size:100;uniqueIndex:idx_blueprints_site_name"`
+	Description  string    `json:"description" gorm:"size:500"`
+	Title        string    `json:"title" gorm:"size:255"`
+	Content      string    `json:"content" gorm:"type:longtext"`
+	Theme        string    `json:"theme" gorm:"size:100"`
+	Placeholders []string  `json:"placeholders" gorm:"-"` // 标题和内容中的占位符，读取时解析
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Job 后台任务，由任务队列的工作协程执行，失败后按退避时间重试
+type Job struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	Type        string     `json:"type" gorm:"not null;size:50"`
+	Payload     string     `json:"payload" gorm:"type:text"` // JSON格式的任务参数
+	SiteID      uint       `json:"site_id" gorm:"not null;default:0;index"`
+	Status      string     `json:"status" gorm:"not null;default:'pending';size:20;index:idx_jobs_status_run_at"` // pending, running, done, dead
+	Attempts    int        `json:"attempts" gorm:"default:0"`
+	MaxAttempts int        `json:"max_attempts" gorm:"default:5"`
+	RunAt       time.Time  `json:"run_at" gorm:"index:idx_jobs_status_run_at"` // 最早执行时间
+	LockedAt    *time.Time `json:"locked_at"`                                  // 开始执行的时间，用于回收中断的任务
+	LastError   string     `json:"last_error" gorm:"type:text"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// SchedulerLease 定时任务主节点的租约，持有者在到期前续约，到期后其他节点可以接管
+type SchedulerLease struct {
+	Name      string    `json:"name" gorm:"primaryKey;size:100"`
+	Holder    string    `json:"holder" gorm:"not null;size:255"`
+	ExpiresAt time.Time `json:"expires_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SchedulerRun 定时任务的一次执行记录
+type SchedulerRun struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	Name       string     `json:"name" gorm:"not null;size:100;index"`
+	Holder     string     `json:"holder" gorm:"size:255"` // 执行任务的节点
+	StartedAt  time.Time  `json:"started_at" gorm:"index"`
+	FinishedAt *time.Time `json:"finished_at"` // 为空时仍在执行或节点在执行中退出
+	Items      int        `json:"items"`       // 处理的条目数量
+	Error      string     `json:"error" gorm:"type:text"`
+	Skipped    bool       `json:"skipped" gorm:"default:false"` // 同一任务正在其他节点执行，本次未执行
+}
+
+// ArticleDraft 编辑器自动保存的草稿，每个管理员在每篇文章（新建文章时 ArticleID 为空）上保留一份
+type ArticleDraft struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      uint      `json:"user_id" gorm:"not null;default:0;uniqueIndex:idx_article_drafts_owner"` // 0 表示内置管理员
+	SiteID      uint      `json:"site_id" gorm:"not null;default:0;uniqueIndex:idx_article_drafts_owner"`
+	ArticleID   string    `json:"article_id" gorm:"type:varchar(36);not null;default:'';uniqueIndex:idx_article_drafts_owner"`
+	Title       string    `json:"title" gorm:"size:255"`
+	Content     string    `json:"content" gorm:"type:longtext"`
+	BaseVersion int       `json:"base_version"` // 开始编辑时文章的版本，用于发现其他人的修改
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,115 @@
+package scheduler
+
+import (
+	"log"
+	"static-hosting-server/internal/events"
+	"time"
+)
+
+// 定时器最长的等待时间：其他实例修改的文章不会触发本实例的事件，最迟在此时间后重新读取过期时间
+const expiryPollInterval = time.Minute
+
+// 两次检查的最短间隔
+const expiryMinInterval = time.Second
+
+// 处理失败的文章按指数退避重试，期间不影响之后到期的文章；定时清理任务同样会处理这些文章
+const (
+	expiryRetryMin = 5 * time.Second
+	expiryRetryMax = 30 * time.Minute
+)
+
+// expiryTimer 在最近一篇文章的过期时间到达时下线文章，文章修改后重新计算等待时间
+type expiryTimer struct {
+	scheduler *Scheduler
+	wake      chan struct{}
+	done      chan struct{}
+	stopped   chan struct{}
+}
+
+func newExpiryTimer(scheduler *Scheduler) *expiryTimer {
+	return &expiryTimer{
+		scheduler: scheduler,
+		wake:      make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+}
+
+// 订阅文章事件并启动定时器
+func (t *expiryTimer) start(bus *events.Bus) {
+	for _, name := range []string{events.ArticleCreated, events.ArticleUpdated, events.ArticleRestored} {
+		bus.Subscribe(name, func(events.Event) { t.reset() })
+	}
+	go t.run()
+}
+
+func (t *expiryTimer) stop() {
+	close(t.done)
+	<-t.stopped
+}
+
+// 重新计算等待时间
+func (t *expiryTimer) reset() {
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (t *expiryTimer) run() {
+	defer close(t.stopped)
+
+	var (
+		failedBefore *time.Time // 此时间及之前到期、处理失败的文章，等待重试
+		retryAt      time.Time  // 下次重试失败文章的时间
+		backoff      time.Duration
+	)
+	for {
+		next, err := t.scheduler.articleService.NextExpiry(failedBefore)
+		if err != nil {
+			log.Printf("Failed to load next article expiry: %v", err)
+		}
+
+		wait := expiryPollInterval
+		if next != nil {
+			if until := time.Until(*next); until < wait {
+				wait = until
+			}
+		}
+		if failedBefore != nil {
+			if until := time.Until(retryAt); until < wait {
+				wait = until
+			}
+		}
+		if wait < expiryMinInterval {
+			wait = expiryMinInterval
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-t.done:
+			timer.Stop()
+			return
+		case <-t.wake:
+			timer.Stop()
+			continue
+		case <-timer.C:
+		}
+
+		now := time.Now()
+		due := (next != nil && !next.After(now)) || (failedBefore != nil && !retryAt.After(now))
+		if !due || !t.scheduler.leader.IsLeader() {
+			continue
+		}
+		t.scheduler.recordProcessed("expire_due", t.scheduler.expireDueArticles)
+
+		// 执行后仍有已到期的文章说明处理失败，退避后再重试
+		if remaining, err := t.scheduler.articleService.NextExpiry(nil); err == nil && remaining != nil && !remaining.After(now) {
+			backoff = min(max(backoff*2, expiryRetryMin), expiryRetryMax)
+			failedBefore, retryAt = &now, now.Add(backoff)
+			log.Printf("Some due articles could not be expired, retrying in %s", backoff)
+		} else {
+			failedBefore, backoff = nil, 0
+		}
+	}
+}
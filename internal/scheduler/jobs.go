@@ -0,0 +1,108 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"static-hosting-server/internal/config"
+	"static-hosting-server/internal/jobs"
+	"static-hosting-server/internal/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// 立即执行定时任务时使用的后台任务类型
+const JobRunNow = "scheduler.run"
+
+// 配置为 off 的任务不定时执行，只能手动触发
+const scheduleOff = "off"
+
+// Definition 定时任务及其执行时间
+type Definition struct {
+	Name        string `json:"name"`
+	Schedule    string `json:"schedule"` // cron表达式（含秒），为空时不定时执行
+	Description string `json:"description"`
+}
+
+// 定时任务的默认执行时间
+var defaultDefinitions = []Definition{
+	{Name: "cleanup_expired", Schedule: "0 0 * * * *", Description: "发送过期提醒，处理遗漏的过期文章，删除超过宽限期的文章"},
+	{Name: "cert_expiry_check", Schedule: "0 30 3 * * *", Description: "从证书文件更新到期时间，提醒即将过期的证书（不签发或续期证书）"},
+	{Name: "sitemap", Schedule: "0 10 * * * *", Description: "重新生成各站点的 sitemap.xml"},
+	{Name: "orphan_media", Schedule: "0 0 4 * * *", Description: "删除没有被文章引用的上传文件"},
+	{Name: "purge_trash", Schedule: "0 20 4 * * *", Description: "彻底删除回收站中超过保留期的文章"},
+}
+
+// 按配置覆盖默认执行时间后的定时任务列表
+func Definitions(cfg *config.Config) []Definition {
+	definitions := make([]Definition, len(defaultDefinitions))
+	copy(definitions, defaultDefinitions)
+
+	for i := range definitions {
+		schedule, ok := cfg.Scheduler.Schedules[definitions[i].Name]
+		if !ok {
+			continue
+		}
+		schedule = strings.TrimSpace(schedule)
+		if strings.EqualFold(schedule, scheduleOff) {
+			schedule = ""
+		}
+		definitions[i].Schedule = schedule
+	}
+	return definitions
+}
+
+// 按名称查找定时任务
+func Lookup(cfg *config.Config, name string) (Definition, bool) {
+	for _, definition := range Definitions(cfg) {
+		if definition.Name == name {
+			return definition, true
+		}
+	}
+	return Definition{}, false
+}
+
+// 立即执行任务的参数
+type runNowPayload struct {
+	Name string `json:"name"`
+}
+
+// ErrUnknownJob 没有该名称的定时任务
+var ErrUnknownJob = errors.New("unknown scheduler job")
+
+// 通过后台任务队列立即执行定时任务，集群中只会由一个节点执行
+func Trigger(cfg *config.Config, queue *jobs.Queue, name string) error {
+	if _, ok := Lookup(cfg, name); !ok {
+		return ErrUnknownJob
+	}
+	return queue.Enqueue(nil, JobRunNow, 0, runNowPayload{Name: name})
+}
+
+// 各任务最近一次的执行记录
+func LastRuns(db *gorm.DB) (map[string]models.SchedulerRun, error) {
+	var runs []models.SchedulerRun
+	latest := db.Model(&models.SchedulerRun{}).Select("MAX(id)").Group("name")
+	if err := db.Where("id IN (?)", latest).Find(&runs).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]models.SchedulerRun, len(runs))
+	for _, run := range runs {
+		result[run.Name] = run
+	}
+	return result, nil
+}
+
+// 执行手动触发的任务
+func (s *Scheduler) runNow(payload []byte) error {
+	var request runNowPayload
+	if err := json.Unmarshal(payload, &request); err != nil {
+		return err
+	}
+	run, ok := s.tasks[request.Name]
+	if !ok {
+		return fmt.Errorf("unknown scheduler job '%s'", request.Name)
+	}
+	return s.record(request.Name, run)
+}
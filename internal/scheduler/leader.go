@@ -0,0 +1,236 @@
+package scheduler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"static-hosting-server/internal/models"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 定时任务主节点租约的名称
+const leaseName = "scheduler"
+
+// Leader 通过数据库中的租约选出唯一的主节点，只有主节点执行定时任务
+type Leader struct {
+	db     *gorm.DB
+	ttl    time.Duration
+	holder string
+
+	mu         sync.Mutex
+	leaseUntil time.Time // 本节点持有租约的截止时间
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewLeader(db *gorm.DB, ttl time.Duration) *Leader {
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	return &Leader{
+		db:     db,
+		ttl:    ttl,
+		holder: holderID(),
+	}
+}
+
+// 节点标识：主机名、进程号和随机后缀，同一主机上的多个进程也能区分
+func holderID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), randomSuffix())
+}
+
+func randomSuffix() string {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return hex.EncodeToString(suffix)
+}
+
+// 本节点的标识
+func (l *Leader) ID() string {
+	return l.holder
+}
+
+// 立即尝试获取租约，之后定期续约
+func (l *Leader) Start() {
+	l.stop = make(chan struct{})
+	l.done = make(chan struct{})
+	l.campaign()
+
+	go func() {
+		defer close(l.done)
+		ticker := time.NewTicker(l.ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-l.stop:
+				return
+			case <-ticker.C:
+				l.campaign()
+			}
+		}
+	}()
+}
+
+// 停止续约并释放租约，其他节点可以立即接管
+func (l *Leader) Stop() {
+	if l.stop == nil {
+		return
+	}
+	close(l.stop)
+	<-l.done
+
+	if l.IsLeader() {
+		releaseLease(l.db, leaseName, l.holder)
+	}
+	l.mu.Lock()
+	l.leaseUntil = time.Time{}
+	l.mu.Unlock()
+}
+
+// 本节点当前是否为主节点。无法续约（如数据库断开）时租约到期后自动失去主节点身份
+func (l *Leader) IsLeader() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return time.Now().Before(l.leaseUntil)
+}
+
+// 获取或续约租约
+func (l *Leader) campaign() {
+	wasLeader := l.IsLeader()
+	acquired, err := l.tryAcquire()
+	if err != nil {
+		log.Printf("Failed to renew scheduler lease: %v", err)
+		return
+	}
+
+	if acquired && !wasLeader {
+		log.Printf("Scheduler leadership acquired by %s", l.holder)
+	} else if !acquired && wasLeader {
+		log.Printf("Scheduler leadership lost by %s", l.holder)
+		l.mu.Lock()
+		l.leaseUntil = time.Time{}
+		l.mu.Unlock()
+	}
+}
+
+func (l *Leader) tryAcquire() (bool, error) {
+	expiresAt := time.Now().Add(l.ttl)
+	acquired, err := acquireLease(l.db, leaseName, l.holder, expiresAt)
+	if err != nil || !acquired {
+		return false, err
+	}
+
+	l.mu.Lock()
+	l.leaseUntil = expiresAt
+	l.mu.Unlock()
+	return true, nil
+}
+
+// 获取或续约名为name的租约：续约自己的租约，或接管已过期的租约
+func acquireLease(db *gorm.DB, name, holder string, expiresAt time.Time) (bool, error) {
+	result := db.Model(&models.SchedulerLease{}).
+		Where("name = ? AND (holder = ? OR expires_at < ?)", name, holder, time.Now()).
+		Updates(map[string]interface{}{"holder": holder, "expires_at": expiresAt})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	// 租约被其他节点持有，或者还没有租约记录；同时创建时只有一个节点成功
+	var count int64
+	if err := db.Model(&models.SchedulerLease{}).Where("name = ?", name).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+	lease := &models.SchedulerLease{Name: name, Holder: holder, ExpiresAt: expiresAt}
+	if err := db.Create(lease).Error; err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+// 释放自己持有的租约
+func releaseLease(db *gorm.DB, name, holder string) {
+	db.Model(&models.SchedulerLease{}).Where("name = ? AND holder = ?", name, holder).
+		Update("expires_at", time.Now())
+}
+
+// 当前的主节点租约，没有节点持有有效租约时返回nil
+func CurrentLeader(db *gorm.DB) *models.SchedulerLease {
+	var lease models.SchedulerLease
+	if err := db.Where("name = ? AND expires_at > ?", leaseName, time.Now()).First(&lease).Error; err != nil {
+		return nil
+	}
+	return &lease
+}
+
+// 任务锁的租约名称，与主节点租约保存在同一张表中
+func taskLeaseName(task string) string {
+	return "task:" + task
+}
+
+// 任务锁：同一任务同时只能有一次执行，包括不同节点之间以及同一节点上定时执行与手动触发的重叠。
+// 每次执行使用各自的持有者标识（节点标识#随机后缀），执行期间定期续约，节点在执行中退出时租约到期后自动释放
+type taskLock struct {
+	db     *gorm.DB
+	name   string
+	holder string
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// 尝试获取任务锁，任务正在执行时返回nil和执行所在的节点
+func (l *Leader) lockTask(task string) (*taskLock, string, error) {
+	name := taskLeaseName(task)
+	holder := l.holder + "#" + randomSuffix()
+	acquired, err := acquireLease(l.db, name, holder, time.Now().Add(l.ttl))
+	if err != nil {
+		return nil, "", err
+	}
+	if !acquired {
+		var lease models.SchedulerLease
+		l.db.Where("name = ?", name).First(&lease)
+		node, _, _ := strings.Cut(lease.Holder, "#")
+		return nil, node, nil
+	}
+
+	lock := &taskLock{db: l.db, name: name, holder: holder, stop: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		defer close(lock.done)
+		ticker := time.NewTicker(l.ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-lock.stop:
+				return
+			case <-ticker.C:
+				if _, err := acquireLease(l.db, name, holder, time.Now().Add(l.ttl)); err != nil {
+					log.Printf("Failed to renew lock for job %s: %v", task, err)
+				}
+			}
+		}
+	}()
+	return lock, "", nil
+}
+
+// 停止续约并释放任务锁，只释放本次执行持有的租约
+func (t *taskLock) release() {
+	close(t.stop)
+	<-t.done
+	releaseLease(t.db, t.name, t.holder)
+}
@@ -1,58 +1,187 @@
 package scheduler
 
 import (
+	"fmt"
 	"log"
-	"static-hosting-server/internal/config"
+	"static-hosting-server/internal/app"
+	"static-hosting-server/internal/models"
 	"static-hosting-server/internal/services"
+	"sync"
+	"time"
 
 	"github.com/robfig/cron/v3"
 	"gorm.io/gorm"
 )
 
+// 执行记录的保留时间
+const runRetention = 30 * 24 * time.Hour
+
+// 定时任务的执行函数，返回处理的条目数量
+type task func() (int, error)
+
 type Scheduler struct {
 	cron           *cron.Cron
+	db             *gorm.DB
+	leader         *Leader
+	expiry         *expiryTimer
 	articleService *services.ArticleService
-}
-
-func Start(db *gorm.DB) *Scheduler {
-	cfg, err := config.Load()
-	if err != nil {
-		log.Printf("Failed to load config for scheduler: %v", err)
-		return nil
-	}
+	tasks          map[string]task
 
-	articleService := services.NewArticleService(db, cfg)
+	// 定时清理和精确定时器不同时处理过期文章
+	expiryMu sync.Mutex
+}
 
-	c := cron.New(cron.WithSeconds())
+func Start(app *app.App) *Scheduler {
+	// 上一次执行未结束时跳过本次执行
+	c := cron.New(cron.WithSeconds(), cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
 
 	scheduler := &Scheduler{
 		cron:           c,
-		articleService: articleService,
+		db:             app.DB,
+		leader:         NewLeader(app.DB, app.Config.Scheduler.LeaseTTL),
+		articleService: app.Articles,
 	}
+	scheduler.tasks = map[string]task{
+		"cleanup_expired":   scheduler.cleanupExpiredArticles,
+		"cert_expiry_check": app.Domains.RefreshCertificates,
+		"sitemap":           app.Articles.RebuildSitemaps,
+		"orphan_media":      app.Articles.CleanupOrphanMedia,
+		"purge_trash":       app.Articles.PurgeTrash,
+	}
+	scheduler.leader.Start()
 
-	// 每小时检查一次过期文章
-	c.AddFunc("0 0 * * * *", scheduler.cleanupExpiredArticles)
+	for _, definition := range Definitions(app.Config) {
+		if definition.Schedule == "" {
+			continue
+		}
+		if _, err := c.AddFunc(definition.Schedule, scheduler.job(definition.Name)); err != nil {
+			log.Printf("Invalid schedule %q for job %s: %v", definition.Schedule, definition.Name, err)
+		}
+	}
+
+	// 手动触发的任务通过后台任务队列执行
+	app.Jobs.Register(JobRunNow, scheduler.runNow)
+
+	// 文章到期时立即下线，不等待定时清理
+	scheduler.expiry = newExpiryTimer(scheduler)
+	scheduler.expiry.start(app.Events)
 
 	// 启动定时任务
 	c.Start()
-	log.Println("Scheduler started")
+	log.Printf("Scheduler started as %s", scheduler.leader.ID())
 
 	return scheduler
 }
 
-func (s *Scheduler) cleanupExpiredArticles() {
+// 包装定时任务：多个实例中只在主节点上执行
+func (s *Scheduler) job(name string) func() {
+	return func() {
+		if !s.leader.IsLeader() {
+			return
+		}
+		s.record(name, s.tasks[name])
+	}
+}
+
+// 执行任务并记录执行历史。同一任务正在执行时（其他节点上，或本节点上手动触发与定时执行重叠）
+// 跳过本次执行，记录为已跳过
+func (s *Scheduler) record(name string, run task) error {
+	return s.execute(name, run, true)
+}
+
+// 执行任务，只在处理了内容或出错时记录执行历史，用于频繁触发的任务（如到期即时下线）
+func (s *Scheduler) recordProcessed(name string, run task) error {
+	return s.execute(name, run, false)
+}
+
+func (s *Scheduler) execute(name string, run task, always bool) error {
+	lock, holder, err := s.leader.lockTask(name)
+	if err != nil {
+		log.Printf("Failed to lock scheduler job %s: %v", name, err)
+		return err
+	}
+	if lock == nil {
+		if always {
+			now := time.Now()
+			s.db.Create(&models.SchedulerRun{
+				Name: name, Holder: s.leader.ID(), StartedAt: now, FinishedAt: &now, Skipped: true,
+				Error: fmt.Sprintf("skipped: already running on %s", holder),
+			})
+		}
+		log.Printf("Scheduler job %s skipped, already running on %s", name, holder)
+		return nil
+	}
+	defer lock.release()
+
+	record := &models.SchedulerRun{Name: name, Holder: s.leader.ID(), StartedAt: time.Now()}
+	if always {
+		if err := s.db.Create(record).Error; err != nil {
+			log.Printf("Failed to record scheduler run %s: %v", name, err)
+		}
+	}
+
+	items, runErr := run()
+	if runErr != nil {
+		log.Printf("Scheduler job %s failed: %v", name, runErr)
+	}
+
+	finishedAt := time.Now()
+	if always {
+		updates := map[string]interface{}{"finished_at": finishedAt, "items": items}
+		if runErr != nil {
+			updates["error"] = runErr.Error()
+		}
+		if record.ID != 0 {
+			s.db.Model(record).Updates(updates)
+		}
+	} else if items > 0 || runErr != nil {
+		record.FinishedAt, record.Items = &finishedAt, items
+		if runErr != nil {
+			record.Error = runErr.Error()
+		}
+		if err := s.db.Create(record).Error; err != nil {
+			log.Printf("Failed to record scheduler run %s: %v", name, err)
+		}
+	}
+
+	s.db.Where("started_at < ?", finishedAt.Add(-runRetention)).Delete(&models.SchedulerRun{})
+	return runErr
+}
+
+func (s *Scheduler) cleanupExpiredArticles() (int, error) {
 	log.Println("Starting cleanup of expired articles...")
 
-	if err := s.articleService.CleanupExpiredArticles(); err != nil {
-		log.Printf("Failed to cleanup expired articles: %v", err)
-	} else {
-		log.Println("Expired articles cleanup completed")
+	s.expiryMu.Lock()
+	defer s.expiryMu.Unlock()
+
+	processed, err := s.articleService.CleanupExpiredArticles()
+	if err == nil {
+		log.Printf("Expired articles cleanup completed, %d articles processed", processed)
 	}
+	return processed, err
 }
 
 func (s *Scheduler) Stop() {
 	if s.cron != nil {
-		s.cron.Stop()
+		<-s.cron.Stop().Done()
+		s.expiry.stop()
+		s.leader.Stop()
 		log.Println("Scheduler stopped")
 	}
 }
+
+// 处理已到过期时间的文章
+func (s *Scheduler) expireDueArticles() (int, error) {
+	s.expiryMu.Lock()
+	defer s.expiryMu.Unlock()
+	return s.articleService.ExpireDueArticles()
+}
+
+// 最近的定时任务执行记录
+func ListRuns(db *gorm.DB, limit int) ([]models.SchedulerRun, error) {
+	var runs []models.SchedulerRun
+	if err := db.Order("started_at DESC, id DESC").Limit(limit).Find(&runs).Error; err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
@@ -1,41 +1,186 @@
 package services
 
 import (
+	"bytes"
 	"fmt"
-	"html/template"
+	"io"
 	"os"
 	"path/filepath"
 	"static-hosting-server/internal/config"
+	"static-hosting-server/internal/events"
+	"static-hosting-server/internal/jobs"
 	"static-hosting-server/internal/models"
-	"strings"
+	"static-hosting-server/internal/theme"
 	"time"
+	"unicode/utf8"
 
 	"gorm.io/gorm"
 )
 
 type ArticleService struct {
-	db  *gorm.DB
-	cfg *config.Config
+	db         *gorm.DB
+	cfg        *config.Config
+	domains    *DomainService
+	sites      *SiteService
+	themes     *theme.Manager
+	notifier   *Notifier
+	redirects  *RedirectService
+	blueprints *BlueprintService
+	events     *events.Bus
+	queue      *jobs.Queue
+
+	// 为空时不限制站点（静态API密钥、定时任务等）
+	siteID *uint
+	// 不为空时事件暂存在此，由事务提交后统一发送，见 BulkArticles
+	pending *[]events.Event
 }
 
 func NewArticleService(db *gorm.DB, cfg *config.Config) *ArticleService {
-	return &ArticleService{
-		db:  db,
-		cfg: cfg,
+	return NewServices(db, cfg, theme.NewManager(cfg), nil).Articles
+}
+
+// 返回限定在指定站点内操作的服务，siteID为空时不限制站点
+func (s *ArticleService) ForSite(siteID *uint) *ArticleService {
+	scoped := *s
+	scoped.siteID = siteID
+	return &scoped
+}
+
+// 当前站点范围内的文章查询
+func (s *ArticleService) articles() *gorm.DB {
+	query := s.db.Model(&models.Article{})
+	if s.siteID != nil {
+		query = query.Where("site_id = ?", *s.siteID)
+	}
+	return query
+}
+
+// 新建文章所属的站点，未限定站点时为默认站点
+func (s *ArticleService) currentSiteID() uint {
+	if s.siteID == nil {
+		return 0
+	}
+	return *s.siteID
+}
+
+// ArticleInput 创建或更新文章的字段，更新时零值表示不修改
+type ArticleInput struct {
+	Title     string
+	Content   string
+	Slug      string
+	Status    string
+	ExpiresAt *time.Time
+	DomainID  *uint // 更新时传 0 恢复默认域名
+	Theme     string
+
+	Visibility string
+	Password   string // 明文访问密码，仅在可见性为 password 时使用
+
+	ExpiryAction string // 过期后的处理方式，见 Expiry* 常量
+	RedirectURL  string // 过期处理方式为 redirect 时的跳转地址
+
+	Meta ArticleMetaInput // SEO和分享卡片
+
+	// 仅创建时使用：从文章模板生成标题、内容和主题，Title 等字段不为空时优先
+	BlueprintID *uint
+	Variables   map[string]string
+
+	// 仅更新时使用：不为空时只在文章的当前版本与之相同时修改，否则返回 ErrEditConflict
+	ExpectedVersion *int
+	// 仅更新时使用：要清除的字段（JSON名称），恢复为空值或默认值，见 clearableFields
+	Clear []string
+}
+
+// 更新时可以清除的字段；SEO字段通过 ArticleMetaInput 中的空字符串清除
+var clearableFields = map[string]bool{
+	"content":       true,
+	"expires_at":    true, // 不过期
+	"domain_id":     true, // 默认域名
+	"theme":         true, // 跟随站点或全局主题
+	"visibility":    true, // 公开
+	"password":      true,
+	"expiry_action": true, // 下线页面
+	"redirect_url":  true,
+}
+
+// 文章标题的最大长度（字符数），与数据库列的长度相同
+const maxTitleLength = 255
+
+// ValidArticleStatus 是否为可以通过接口或后台设置的文章状态
+func ValidArticleStatus(status string) bool {
+	switch status {
+	case "draft", "published", "archived", "expired":
+		return true
+	}
+	return false
+}
+
+// 检查创建和更新共有的字段，返回所有不合法的字段。current 为更新前的文章，创建时为nil
+func (s *ArticleService) validateInput(input *ArticleInput, current *models.Article) FieldErrors {
+	invalid := FieldErrors{}
+	if utf8.RuneCountInString(input.Title) > maxTitleLength {
+		invalid["title"] = fmt.Sprintf("title must not exceed %d characters", maxTitleLength)
+	}
+	if input.Status != "" && !ValidArticleStatus(input.Status) {
+		invalid["status"] = fmt.Sprintf("invalid status '%s', must be one of draft, published, archived, expired", input.Status)
+	}
+	if input.Slug != "" && (current == nil || input.Slug != current.Slug) {
+		if err := s.validateSlug(input.Slug); err != nil {
+			invalid["slug"] = err.Error()
+		}
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		// 原样提交已过期文章的过期时间不算修改
+		if current == nil || current.ExpiresAt == nil || !current.ExpiresAt.Equal(*input.ExpiresAt) {
+			invalid["expires_at"] = "expires_at must be in the future"
+		}
 	}
+	if input.Visibility != "" && !validVisibility(input.Visibility) {
+		invalid["visibility"] = fmt.Sprintf("invalid visibility '%s'", input.Visibility)
+	}
+	return invalid
 }
 
 // 创建文章
-func (s *ArticleService) CreateArticle(title, content, slug, status string, expiresAt *time.Time) (*models.Article, error) {
-	// 如果没有提供slug，从标题生成
-	if slug == "" {
-		slug = s.generateSlugFromTitle(title)
+func (s *ArticleService) CreateArticle(input ArticleInput) (*models.Article, error) {
+	siteID := s.currentSiteID()
+	if siteID != 0 {
+		if _, err := s.sites.GetSiteByID(siteID); err != nil {
+			return nil, FieldErrors{"site_id": fmt.Sprintf("site %d not found", siteID)}
+		}
 	}
 
-	// 检查slug是否已存在
-	var existingArticle models.Article
-	if err := s.db.Where("slug = ?", slug).First(&existingArticle).Error; err == nil {
-		return nil, fmt.Errorf("article with slug '%s' already exists", slug)
+	if input.BlueprintID != nil {
+		if err := s.applyBlueprint(siteID, &input); err != nil {
+			return nil, err
+		}
+	}
+	invalid := s.validateInput(&input, nil)
+	if input.Title == "" {
+		invalid["title"] = "title is required"
+	}
+	if input.Content == "" {
+		invalid["content"] = "content is required"
+	}
+	if err := invalid.err(); err != nil {
+		return nil, err
+	}
+
+	title, content, slug, status := input.Title, input.Content, input.Slug, input.Status
+
+	if slug == "" {
+		// 如果没有提供slug，从标题生成，重名时自动追加序号
+		generated, err := s.generateSlugFromTitle(siteID, title)
+		if err != nil {
+			return nil, err
+		}
+		slug = generated
+	} else {
+		// 检查slug在站点内是否已存在
+		var existingArticle models.Article
+		if err := s.db.Where("site_id = ? AND slug = ?", siteID, slug).First(&existingArticle).Error; err == nil {
+			return nil, conflictf("article with slug '%s' already exists", slug)
+		}
 	}
 
 	// 设置默认状态
@@ -43,139 +188,414 @@ func (s *ArticleService) CreateArticle(title, content, slug, status string, expi
 		status = "draft"
 	}
 
+	if err := s.checkDomain(siteID, input.DomainID); err != nil {
+		return nil, fieldError("domain_id", err)
+	}
+	if err := s.checkTheme(input.Theme); err != nil {
+		return nil, fieldError("theme", err)
+	}
+
+	visibility := input.Visibility
+	if visibility == "" {
+		visibility = VisibilityPublic
+	}
+
+	var passwordHash string
+	if visibility == VisibilityPassword {
+		if input.Password == "" {
+			return nil, FieldErrors{"password": "password is required for password-protected articles"}
+		}
+		hash, err := hashArticlePassword(input.Password)
+		if err != nil {
+			return nil, err
+		}
+		passwordHash = hash
+	}
+
+	expiryAction := input.ExpiryAction
+	if expiryAction == "" {
+		expiryAction = ExpiryUnpublish
+	}
+	if err := validateExpiryAction(expiryAction, input.RedirectURL); err != nil {
+		return nil, expiryActionError(expiryAction, err)
+	}
+
 	article := &models.Article{
+		SiteID:    siteID,
 		Title:     title,
 		Content:   content,
 		Slug:      slug,
 		Status:    status,
-		ExpiresAt: expiresAt,
-	}
+		ExpiresAt: input.ExpiresAt,
+		DomainID:  input.DomainID,
+		Theme:     input.Theme,
+
+		Visibility:   visibility,
+		PasswordHash: passwordHash,
 
-	if err := s.db.Create(article).Error; err != nil {
+		ExpiryAction: expiryAction,
+		RedirectURL:  input.RedirectURL,
+	}
+	if err := input.Meta.apply(article); err != nil {
 		return nil, err
 	}
 
-	// 如果状态为已发布，生成静态文件
-	if status == "published" {
-		if err := s.generateStaticFiles(article); err != nil {
-			// 记录错误但不回滚创建操作
-			fmt.Printf("Failed to generate static files for article %d: %v\n", article.ID, err)
+	// 已发布的文章与生成静态文件的任务一起提交
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(article).Error; err != nil {
+			return err
+		}
+		if status == "published" {
+			return s.enqueueRender(tx, article)
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	// 文章占用了之前设置跳转的路径
+	s.redirects.releasePath(s.db, siteID, articlePathPrefix+slug)
+
+	s.publish(events.ArticleCreated, article)
+	if status == "published" {
+		s.publish(events.ArticlePublished, article)
+	}
 	return article, nil
 }
 
+// 用文章模板填充未指定的标题、内容和主题
+func (s *ArticleService) applyBlueprint(siteID uint, input *ArticleInput) error {
+	blueprint, err := s.blueprints.GetBlueprintByID(*input.BlueprintID)
+	if err != nil || !s.blueprints.Usable(blueprint, siteID) {
+		return FieldErrors{"blueprint_id": fmt.Sprintf("blueprint %d not found", *input.BlueprintID)}
+	}
+
+	title, content, err := s.blueprints.Render(blueprint, input.Variables)
+	if err != nil {
+		return err
+	}
+	if input.Title == "" {
+		input.Title = title
+	}
+	if input.Content == "" {
+		input.Content = content
+	}
+	if input.Theme == "" {
+		input.Theme = blueprint.Theme
+	}
+	return nil
+}
+
 // 根据ID获取文章
 func (s *ArticleService) GetArticleByID(id string) (*models.Article, error) {
 	var article models.Article
-	if err := s.db.Where("id = ?", id).First(&article).Error; err != nil {
+	if err := s.articles().Where("id = ?", id).First(&article).Error; err != nil {
 		return nil, err
 	}
 	return &article, nil
 }
 
-// 根据slug获取已发布的文章
+// 根据slug获取可公开访问的文章，包括已过期的文章，由调用方按过期策略处理
 func (s *ArticleService) GetPublishedArticleBySlug(slug string) (*models.Article, error) {
 	var article models.Article
-	if err := s.db.Where("slug = ? AND status = ?", slug, "published").First(&article).Error; err != nil {
+	if err := s.articles().Where("slug = ? AND status IN ?", slug, []string{"published", "archived", "expired"}).First(&article).Error; err != nil {
 		return nil, err
 	}
 	return &article, nil
 }
 
 // 更新文章
-func (s *ArticleService) UpdateArticle(id string, title, content, status string, expiresAt *time.Time) (*models.Article, error) {
+func (s *ArticleService) UpdateArticle(id string, input ArticleInput) (*models.Article, error) {
 	var article models.Article
-	if err := s.db.Where("id = ?", id).First(&article).Error; err != nil {
+	if err := s.articles().Where("id = ?", id).First(&article).Error; err != nil {
+		return nil, err
+	}
+	if input.ExpectedVersion != nil && *input.ExpectedVersion != article.Version {
+		return nil, ErrEditConflict
+	}
+
+	invalid := s.validateInput(&input, &article)
+	clear := make(map[string]bool, len(input.Clear))
+	for _, field := range input.Clear {
+		if !clearableFields[field] {
+			invalid[field] = "cannot be cleared"
+		}
+		clear[field] = true
+	}
+	if err := invalid.err(); err != nil {
 		return nil, err
 	}
 
 	oldStatus := article.Status
+	oldSlug := article.Slug
 
 	// 更新字段
 	updates := make(map[string]interface{})
-	if title != "" {
-		updates["title"] = title
+	if input.Title != "" {
+		updates["title"] = input.Title
+	}
+	if input.Slug != "" && input.Slug != article.Slug {
+		var existingArticle models.Article
+		if err := s.db.Where("site_id = ? AND slug = ?", article.SiteID, input.Slug).First(&existingArticle).Error; err == nil {
+			return nil, conflictf("article with slug '%s' already exists", input.Slug)
+		}
+		updates["slug"] = input.Slug
 	}
-	if content != "" {
-		updates["content"] = content
+	if input.Content != "" {
+		updates["content"] = input.Content
+	} else if clear["content"] {
+		updates["content"] = ""
 	}
-	if status != "" {
-		updates["status"] = status
+	if input.Status != "" {
+		updates["status"] = input.Status
 	}
-	if expiresAt != nil {
-		updates["expires_at"] = expiresAt
+	if input.ExpiresAt != nil {
+		updates["expires_at"] = input.ExpiresAt
+		// 过期时间变化后重新发送过期提醒
+		if article.ExpiresAt == nil || !article.ExpiresAt.Equal(*input.ExpiresAt) {
+			updates["warned_at"] = nil
+		}
+	} else if clear["expires_at"] {
+		updates["expires_at"] = nil
+		updates["warned_at"] = nil
+	}
+	if clear["domain_id"] {
+		updates["domain_id"] = nil
+	} else if input.DomainID != nil {
+		if *input.DomainID == 0 {
+			// 0 表示恢复使用默认域名
+			updates["domain_id"] = nil
+		} else {
+			if err := s.checkDomain(article.SiteID, input.DomainID); err != nil {
+				return nil, fieldError("domain_id", err)
+			}
+			updates["domain_id"] = input.DomainID
+		}
+	}
+	if input.Theme != "" {
+		if err := s.checkTheme(input.Theme); err != nil {
+			return nil, fieldError("theme", err)
+		}
+		updates["theme"] = input.Theme
+	} else if clear["theme"] {
+		updates["theme"] = ""
 	}
 
-	if err := s.db.Model(&article).Updates(updates).Error; err != nil {
-		return nil, err
+	visibility := article.Visibility
+	if input.Visibility != "" {
+		visibility = input.Visibility
+		updates["visibility"] = visibility
+	} else if clear["visibility"] {
+		visibility = VisibilityPublic
+		updates["visibility"] = visibility
+	}
+	if visibility == VisibilityPassword {
+		if input.Password != "" {
+			hash, err := hashArticlePassword(input.Password)
+			if err != nil {
+				return nil, err
+			}
+			updates["password_hash"] = hash
+		} else if article.PasswordHash == "" || clear["password"] {
+			return nil, FieldErrors{"password": "password is required for password-protected articles"}
+		}
+	} else if article.PasswordHash != "" {
+		// 取消密码保护时清除密码
+		updates["password_hash"] = ""
+	}
+
+	if input.ExpiryAction != "" || input.RedirectURL != "" || clear["expiry_action"] || clear["redirect_url"] {
+		expiryAction, redirectURL := article.ExpiryAction, article.RedirectURL
+		if input.ExpiryAction != "" {
+			expiryAction = input.ExpiryAction
+		} else if clear["expiry_action"] {
+			expiryAction = ExpiryUnpublish
+		}
+		if input.RedirectURL != "" {
+			redirectURL = input.RedirectURL
+		} else if clear["redirect_url"] {
+			redirectURL = ""
+		}
+		if err := validateExpiryAction(expiryAction, redirectURL); err != nil {
+			return nil, expiryActionError(expiryAction, err)
+		}
+		updates["expiry_action"] = expiryAction
+		updates["redirect_url"] = redirectURL
 	}
 
-	// 重新获取更新后的文章
-	if err := s.db.Where("id = ?", id).First(&article).Error; err != nil {
+	metaUpdates, err := input.Meta.updates()
+	if err != nil {
 		return nil, err
 	}
+	for column, value := range metaUpdates {
+		updates[column] = value
+	}
 
-	// 处理状态变化
-	if oldStatus != article.Status {
-		if article.Status == "published" {
-			// 生成静态文件
-			if err := s.generateStaticFiles(&article); err != nil {
-				fmt.Printf("Failed to generate static files for article %d: %v\n", article.ID, err)
+	updates["version"] = gorm.Expr("version + 1")
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// 按读取时的版本更新，期间被其他请求修改时不覆盖
+		result := tx.Model(&article).Where("version = ?", article.Version).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrEditConflict
+		}
+
+		// 重新获取更新后的文章
+		if err := tx.Where("id = ?", id).First(&article).Error; err != nil {
+			return err
+		}
+
+		// 公开过的文章（不是草稿）修改slug后旧地址301跳转到新地址，旧静态文件由任务替换为跳转页
+		if article.Slug != oldSlug && oldStatus != "draft" {
+			stubs, err := s.redirects.recordSlugChange(tx, article.SiteID, oldSlug, article.Slug)
+			if err != nil {
+				return fmt.Errorf("failed to record redirect: %w", err)
 			}
-		} else if oldStatus == "published" {
-			// 删除静态文件
-			if err := s.removeStaticFiles(article.Slug); err != nil {
-				fmt.Printf("Failed to remove static files for article %d: %v\n", article.ID, err)
+			for _, slug := range stubs {
+				if err := s.enqueueRemove(tx, &models.Article{SiteID: article.SiteID, Slug: slug}); err != nil {
+					return err
+				}
 			}
 		}
-	} else if article.Status == "published" {
-		// 更新静态文件
-		if err := s.generateStaticFiles(&article); err != nil {
-			fmt.Printf("Failed to update static files for article %d: %v\n", article.ID, err)
+
+		// 已发布的文章重新生成静态文件，从已发布等状态变为其他状态时删除或替换静态文件（包括归档页和跳转页）
+		if article.Status == "published" || (oldStatus != article.Status && oldStatus != "draft") {
+			return s.enqueueRender(tx, &article)
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	s.publish(events.ArticleUpdated, &article)
+	if oldStatus != article.Status && article.Status == "published" {
+		s.publish(events.ArticlePublished, &article)
+	}
 	return &article, nil
 }
 
-// 删除文章
-func (s *ArticleService) DeleteArticle(id string) error {
+// 删除文章（移入回收站），version不为空时只在文章的当前版本与之相同时删除，否则返回 ErrEditConflict
+func (s *ArticleService) DeleteArticle(id string, version *int) error {
 	var article models.Article
-	if err := s.db.Where("id = ?", id).First(&article).Error; err != nil {
+	if err := s.articles().Where("id = ?", id).First(&article).Error; err != nil {
 		return err
 	}
+	if version == nil {
+		version = &article.Version
+	}
 
-	// 删除静态文件
-	if article.Status == "published" {
-		if err := s.removeStaticFiles(article.Slug); err != nil {
-			fmt.Printf("Failed to remove static files for article %s: %v\n", article.ID, err)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("version = ?", *version).Delete(&article)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrEditConflict
+		}
+		// 移入回收站后slug可以被其他文章使用
+		if err := tx.Unscoped().Model(&article).Update("trash_key", article.ID).Error; err != nil {
+			return err
+		}
+		// 删除静态文件（包括归档页和跳转页）
+		if article.Status != "draft" {
+			return s.enqueueRemove(tx, &article)
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	return s.db.Delete(&article).Error
+	s.publish(events.ArticleDeleted, &article)
+	return nil
+}
+
+// 删除文章，并把文章原地址跳转到target，version的含义与 DeleteArticle 相同
+func (s *ArticleService) DeleteArticleWithRedirect(id, target string, version *int) error {
+	if !validLinkTarget(target) {
+		return FieldErrors{"redirect_to": fmt.Sprintf("invalid redirect target '%s'", target)}
+	}
+
+	article, err := s.GetArticleByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.DeleteArticle(id, version); err != nil {
+		return err
+	}
+
+	_, err = s.redirects.CreateRedirect(article.SiteID, articlePathPrefix+article.Slug, target, 0)
+	return err
+}
+
+// 发布文章事件
+func (s *ArticleService) publish(name string, article *models.Article) {
+	event := events.Event{
+		Name:      name,
+		SiteID:    article.SiteID,
+		SubjectID: article.ID,
+		Data:      article,
+	}
+	if s.pending != nil {
+		*s.pending = append(*s.pending, event)
+		return
+	}
+	s.events.Publish(event)
+}
+
+// 文章的规范访问地址
+func (s *ArticleService) PublicURL(article *models.Article) string {
+	return s.domains.ArticleURL(article)
+}
+
+// 检查文章要关联的自定义域名是否存在，且按Host路由到文章所在站点
+func (s *ArticleService) checkDomain(siteID uint, domainID *uint) error {
+	if domainID == nil {
+		return nil
+	}
+	domain, err := s.domains.GetDomainByID(*domainID)
+	if err != nil {
+		return fmt.Errorf("domain %d not found", *domainID)
+	}
+	if !domainServesSite(domain, siteID) {
+		return fmt.Errorf("domain %d belongs to another site", *domainID)
+	}
+	return nil
+}
+
+// 获取可公开索引的已发布文章（用于站点地图、订阅等），不包含仅凭链接访问和密码保护的文章
+func (s *ArticleService) ListIndexableArticles() ([]models.Article, error) {
+	var articles []models.Article
+	if err := s.articles().Where("status = ? AND (visibility = ? OR visibility = '' OR visibility IS NULL)", "published", VisibilityPublic).
+		Order("updated_at DESC").Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	return articles, nil
 }
 
 // 获取文章列表
-func (s *ArticleService) ListArticles(page, limit int, status string) ([]models.Article, int64, error) {
+func (s *ArticleService) ListArticles(page int, opts ArticleListOptions) ([]models.Article, int64, error) {
 	var articles []models.Article
 	var total int64
 
-	query := s.db.Model(&models.Article{})
-
-	if status != "" {
-		query = query.Where("status = ?", status)
+	if page < 1 {
+		page = 1
 	}
+	limit := opts.limit()
 
 	// 获取总数
-	if err := query.Count(&total).Error; err != nil {
+	if err := s.listQuery(ArticleListOptions{Status: opts.Status}).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	// 分页查询
+	// 分页查询，id 保证创建时间相同时顺序稳定
 	offset := (page - 1) * limit
-	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&articles).Error; err != nil {
+	if err := s.listQuery(opts).Offset(offset).Limit(limit).Order("created_at DESC, id DESC").Find(&articles).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -184,92 +604,94 @@ func (s *ArticleService) ListArticles(page, limit int, status string) ([]models.
 
 // 生成静态HTML文件
 func (s *ArticleService) generateStaticFiles(article *models.Article) error {
+	// 密码保护的文章不输出内容，静态页面跳转到需要验证密码的访问地址
+	if IsPasswordProtected(article) {
+		return writeRedirectStub(s.articleDir(article), s.PublicURL(article))
+	}
+
+	// 先渲染到内存，避免模板出错时留下不完整的页面
+	var buf bytes.Buffer
+	if err := s.RenderArticle(&buf, article, nil); err != nil {
+		return err
+	}
+
 	// 创建文章目录
-	articleDir := filepath.Join(s.cfg.Storage.StaticPath, "articles", article.Slug)
+	articleDir := s.articleDir(article)
 	if err := os.MkdirAll(articleDir, 0755); err != nil {
 		return fmt.Errorf("failed to create article directory: %w", err)
 	}
 
-	// 读取模板并添加自定义函数
-	tmplPath := "templates/article.html"
-	tmpl, err := template.New("article.html").Funcs(template.FuncMap{
-		"safeHTML": func(s string) template.HTML { return template.HTML(s) },
-	}).ParseFiles(tmplPath)
-	if err != nil {
-		return fmt.Errorf("failed to parse template: %w", err)
-	}
-
 	// 创建HTML文件
 	htmlPath := filepath.Join(articleDir, "index.html")
-	file, err := os.Create(htmlPath)
-	if err != nil {
+	if err := os.WriteFile(htmlPath, buf.Bytes(), 0644); err != nil {
 		return fmt.Errorf("failed to create HTML file: %w", err)
 	}
-	defer file.Close()
-
-	// 渲染模板
-	data := struct {
-		Article *models.Article
-		Domain  string
-	}{
-		Article: article,
-		Domain:  s.cfg.Server.Domain,
-	}
-
-	if err := tmpl.Execute(file, data); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
-	}
 
 	return nil
 }
 
-// 删除静态文件
-func (s *ArticleService) removeStaticFiles(slug string) error {
-	articleDir := filepath.Join(s.cfg.Storage.StaticPath, "articles", slug)
-	return os.RemoveAll(articleDir)
-}
+// 重新生成当前站点范围内所有已发布文章的静态文件，返回成功生成的数量
+func (s *ArticleService) RebuildStaticFiles() (int, error) {
+	var articles []models.Article
+	if err := s.articles().Where("status = ?", "published").Find(&articles).Error; err != nil {
+		return 0, err
+	}
 
-// 从标题生成slug
-func (s *ArticleService) generateSlugFromTitle(title string) string {
-	// 简化的slug生成逻辑
-	slug := strings.ToLower(title)
-	slug = strings.ReplaceAll(slug, " ", "-")
-	slug = strings.ReplaceAll(slug, "_", "-")
-
-	// 移除特殊字符（这里简化处理）
-	var result strings.Builder
-	for _, char := range slug {
-		if (char >= 'a' && char <= 'z') || (char >= '0' && char <= '9') || char == '-' {
-			result.WriteRune(char)
+	rebuilt := 0
+	for i := range articles {
+		if err := s.generateStaticFiles(&articles[i]); err != nil {
+			fmt.Printf("Failed to rebuild static files for article %s: %v\n", articles[i].ID, err)
+			continue
 		}
+		rebuilt++
 	}
+	return rebuilt, nil
+}
 
-	slug = result.String()
+// 使用文章的主题渲染页面，实时访问和静态文件生成共用此流程
+func (s *ArticleService) RenderArticle(w io.Writer, article *models.Article, extra map[string]interface{}) error {
+	var site *models.Site
+	if article.SiteID != 0 {
+		site, _ = s.sites.GetSiteByID(article.SiteID)
+	}
 
-	// 添加时间戳以确保唯一性
-	timestamp := time.Now().Unix()
-	return fmt.Sprintf("%s-%d", slug, timestamp)
+	data := map[string]interface{}{
+		"article": article,
+		"domain":  s.domains.BaseURL(article),
+		// 不公开索引的文章禁止搜索引擎收录
+		"noindex": !IsIndexable(article),
+		// 已过期但保留展示的文章显示归档提示
+		"archived": IsArchived(article),
+		"meta":     s.articleMeta(article, site),
+	}
+	for key, value := range extra {
+		data[key] = value
+	}
+
+	if err := s.themes.Render(w, s.themes.Resolve(article, site), data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	return nil
 }
 
-// 清理过期文章
-func (s *ArticleService) CleanupExpiredArticles() error {
-	var expiredArticles []models.Article
-	if err := s.db.Where("expires_at IS NOT NULL AND expires_at < ? AND status = ?",
-		time.Now(), "published").Find(&expiredArticles).Error; err != nil {
-		return err
+// 检查主题是否可用
+func (s *ArticleService) checkTheme(name string) error {
+	if name == "" {
+		return nil
 	}
+	return s.themes.Validate(name)
+}
 
-	for _, article := range expiredArticles {
-		// 删除静态文件
-		if err := s.removeStaticFiles(article.Slug); err != nil {
-			fmt.Printf("Failed to remove static files for expired article %d: %v\n", article.ID, err)
-		}
+// 删除静态文件
+func (s *ArticleService) removeStaticFiles(article *models.Article) error {
+	return os.RemoveAll(s.articleDir(article))
+}
 
-		// 更新状态为过期
-		if err := s.db.Model(&article).Update("status", "expired").Error; err != nil {
-			fmt.Printf("Failed to update status for expired article %d: %v\n", article.ID, err)
-		}
-	}
+// 文章静态文件目录，非默认站点位于站点的存储前缀下
+func (s *ArticleService) articleDir(article *models.Article) string {
+	return s.staticDir(article.SiteID, article.Slug)
+}
 
-	return nil
+func (s *ArticleService) staticDir(siteID uint, slug string) string {
+	return filepath.Join(s.cfg.Storage.StaticPath, s.sites.StoragePrefix(siteID), "articles", slug)
 }
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"static-hosting-server/internal/config"
+	"static-hosting-server/internal/models"
+	"static-hosting-server/internal/theme"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// 占位符：{{name}} 插入转义后的文本，{{{name}}} 插入原始HTML（仅用于内容）
+var placeholderPattern = regexp.MustCompile(`\{\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}\}|\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+type BlueprintService struct {
+	db     *gorm.DB
+	cfg    *config.Config
+	sites  *SiteService
+	themes *theme.Manager
+}
+
+func NewBlueprintService(db *gorm.DB, cfg *config.Config) *BlueprintService {
+	return NewServices(db, cfg, theme.NewManager(cfg), nil).Blueprints
+}
+
+// BlueprintInput 创建或更新文章模板的字段，更新时零值表示不修改
+type BlueprintInput struct {
+	Name        string
+	Description string
+	Title       string
+	Content     string
+	Theme       string
+}
+
+// 创建文章模板
+func (s *BlueprintService) CreateBlueprint(siteID uint, input BlueprintInput) (*models.Blueprint, error) {
+	invalid := FieldErrors{}
+	if input.Name == "" {
+		invalid["name"] = "blueprint name is required"
+	}
+	if input.Content == "" {
+		invalid["content"] = "blueprint content is required"
+	}
+	if err := s.checkTheme(input.Theme); err != nil {
+		invalid["theme"] = err.Error()
+	}
+	if err := invalid.err(); err != nil {
+		return nil, err
+	}
+	if err := s.sites.CheckSiteID(siteID); err != nil {
+		return nil, err
+	}
+
+	var existing models.Blueprint
+	if err := s.db.Where("site_id = ? AND name = ?", siteID, input.Name).First(&existing).Error; err == nil {
+		return nil, conflictf("blueprint '%s' already exists", input.Name)
+	}
+
+	blueprint := &models.Blueprint{
+		SiteID:      siteID,
+		Name:        input.Name,
+		Description: input.Description,
+		Title:       input.Title,
+		Content:     input.Content,
+		Theme:       input.Theme,
+	}
+	if err := s.db.Create(blueprint).Error; err != nil {
+		return nil, err
+	}
+
+	blueprint.Placeholders = Placeholders(blueprint)
+	return blueprint, nil
+}
+
+// 更新文章模板
+func (s *BlueprintService) UpdateBlueprint(id uint, input BlueprintInput) (*models.Blueprint, error) {
+	blueprint, err := s.GetBlueprintByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if input.Name != "" && input.Name != blueprint.Name {
+		var existing models.Blueprint
+		if err := s.db.Where("site_id = ? AND name = ?", blueprint.SiteID, input.Name).First(&existing).Error; err == nil {
+			return nil, conflictf("blueprint '%s' already exists", input.Name)
+		}
+		updates["name"] = input.Name
+	}
+	if input.Description != "" {
+		updates["description"] = input.Description
+	}
+	if input.Title != "" {
+		updates["title"] = input.Title
+	}
+	if input.Content != "" {
+		updates["content"] = input.Content
+	}
+	if input.Theme != "" {
+		if err := s.checkTheme(input.Theme); err != nil {
+			return nil, fieldError("theme", err)
+		}
+		updates["theme"] = input.Theme
+	}
+
+	if err := s.db.Model(blueprint).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	return s.GetBlueprintByID(id)
+}
+
+// 根据ID获取文章模板
+func (s *BlueprintService) GetBlueprintByID(id uint) (*models.Blueprint, error) {
+	var blueprint models.Blueprint
+	if err := s.db.First(&blueprint, id).Error; err != nil {
+		return nil, err
+	}
+	blueprint.Placeholders = Placeholders(&blueprint)
+	return &blueprint, nil
+}
+
+// 获取站点可用的文章模板（包括默认站点的模板），siteID为空时返回所有模板
+func (s *BlueprintService) ListBlueprints(siteID *uint) ([]models.Blueprint, error) {
+	query := s.db.Order("name")
+	if siteID != nil {
+		query = query.Where("site_id IN ?", []uint{0, *siteID})
+	}
+
+	var blueprints []models.Blueprint
+	if err := query.Find(&blueprints).Error; err != nil {
+		return nil, err
+	}
+	for i := range blueprints {
+		blueprints[i].Placeholders = Placeholders(&blueprints[i])
+	}
+	return blueprints, nil
+}
+
+// 删除文章模板，已创建的文章不受影响
+func (s *BlueprintService) DeleteBlueprint(id uint) error {
+	return s.db.Delete(&models.Blueprint{}, id).Error
+}
+
+// 文章模板是否可用于指定站点
+func (s *BlueprintService) Usable(blueprint *models.Blueprint, siteID uint) bool {
+	return blueprint.SiteID == 0 || blueprint.SiteID == siteID
+}
+
+// 用变量替换占位符，返回文章标题和内容，缺少变量时返回错误
+func (s *BlueprintService) Render(blueprint *models.Blueprint, variables map[string]string) (string, string, error) {
+	var missing []string
+	for _, name := range Placeholders(blueprint) {
+		if _, ok := variables[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", "", FieldErrors{"variables": "missing blueprint variables: " + strings.Join(missing, ", ")}
+	}
+
+	// 标题为纯文本，输出页面时统一转义
+	title := placeholderPattern.ReplaceAllStringFunc(blueprint.Title, func(match string) string {
+		_, name := parsePlaceholder(match)
+		return variables[name]
+	})
+	content := placeholderPattern.ReplaceAllStringFunc(blueprint.Content, func(match string) string {
+		raw, name := parsePlaceholder(match)
+		if raw {
+			return variables[name]
+		}
+		return html.EscapeString(variables[name])
+	})
+	return title, content, nil
+}
+
+// 标题和内容中出现的占位符名称，按名称排序
+func Placeholders(blueprint *models.Blueprint) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, text := range []string{blueprint.Title, blueprint.Content} {
+		for _, match := range placeholderPattern.FindAllString(text, -1) {
+			_, name := parsePlaceholder(match)
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// 解析占位符，返回是否插入原始HTML及变量名
+func parsePlaceholder(match string) (bool, string) {
+	groups := placeholderPattern.FindStringSubmatch(match)
+	if groups[1] != "" {
+		return true, groups[1]
+	}
+	return false, groups[2]
+}
+
+// 检查主题是否可用
+func (s *BlueprintService) checkTheme(name string) error {
+	if name == "" {
+		return nil
+	}
+	return s.themes.Validate(name)
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"static-hosting-server/internal/events"
+	"static-hosting-server/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 批量操作
+const (
+	BulkPublish   = "publish"   // 发布
+	BulkUnpublish = "unpublish" // 改为草稿，下线页面
+	BulkArchive   = "archive"   // 归档
+	BulkExtend    = "extend"    // 过期时间延长 ExtendBy，见 ExtendExpiry
+	BulkDelete    = "delete"    // 删除
+)
+
+// MaxBulkArticles 一次批量操作最多处理的文章数
+const MaxBulkArticles = 500
+
+// ErrBulkAborted 原子批量操作中其他文章处理失败，本文章的修改已回滚或未执行
+var ErrBulkAborted = errors.New("not applied because another article in the batch failed")
+
+// BulkFilter 按条件选择批量操作的文章，条件之间为“且”
+type BulkFilter struct {
+	Status        string
+	ExpiresBefore *time.Time // 过期时间早于此时间
+}
+
+// BulkInput 批量操作，IDs 和 Filter 只能指定一个
+type BulkInput struct {
+	Action   string
+	IDs      []string
+	Filter   *BulkFilter
+	ExtendBy time.Duration // 仅 extend 使用
+	// 所有文章在同一事务中处理，任何一篇失败时全部回滚；否则逐篇处理，互不影响
+	Atomic bool
+}
+
+// BulkResult 单篇文章的处理结果，Err 为空表示成功
+type BulkResult struct {
+	ID      string
+	Article *models.Article // 处理后的文章，删除时为空
+	Err     error
+}
+
+// 批量处理文章，返回每篇文章的结果。请求本身不合法时返回 FieldErrors
+func (s *ArticleService) BulkArticles(input BulkInput) ([]BulkResult, error) {
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	ids, err := s.bulkTargets(input)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkResult, len(ids))
+	if !input.Atomic {
+		for i, id := range ids {
+			results[i] = s.bulkApply(id, input)
+		}
+		return results, nil
+	}
+
+	// 事件在提交后才发送，回滚时丢弃
+	var pending []events.Event
+	failed := -1
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		scoped := *s
+		scoped.db = tx
+		scoped.pending = &pending
+		for i, id := range ids {
+			results[i] = scoped.bulkApply(id, input)
+			if results[i].Err != nil {
+				failed = i
+				return results[i].Err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		for i := range results {
+			if i != failed {
+				results[i] = BulkResult{ID: ids[i], Err: ErrBulkAborted}
+			}
+		}
+		if failed < 0 {
+			// 提交失败
+			return nil, err
+		}
+		return results, nil
+	}
+	for _, event := range pending {
+		s.events.Publish(event)
+	}
+	return results, nil
+}
+
+// 检查批量操作的参数
+func (input *BulkInput) validate() error {
+	invalid := FieldErrors{}
+	switch input.Action {
+	case BulkPublish, BulkUnpublish, BulkArchive, BulkDelete:
+	case BulkExtend:
+		if input.ExtendBy <= 0 {
+			invalid["extend_hours"] = "extend_hours must be positive"
+		}
+	default:
+		invalid["action"] = fmt.Sprintf("invalid action '%s', must be one of publish, unpublish, archive, extend, delete", input.Action)
+	}
+
+	switch {
+	case len(input.IDs) > 0 && input.Filter != nil:
+		invalid["ids"] = "specify either ids or filter, not both"
+	case len(input.IDs) > MaxBulkArticles:
+		invalid["ids"] = fmt.Sprintf("at most %d articles per request", MaxBulkArticles)
+	case input.Filter != nil:
+		if input.Filter.Status == "" && input.Filter.ExpiresBefore == nil {
+			invalid["filter"] = "filter must have at least one condition"
+		} else if input.Filter.Status != "" && !ValidArticleStatus(input.Filter.Status) {
+			invalid["filter"] = fmt.Sprintf("invalid status '%s'", input.Filter.Status)
+		}
+	case len(input.IDs) == 0:
+		invalid["ids"] = "ids or filter is required"
+	}
+	return invalid.err()
+}
+
+// 要处理的文章ID，按请求中的顺序去重；按条件选择时按创建时间排序
+func (s *ArticleService) bulkTargets(input BulkInput) ([]string, error) {
+	if input.Filter == nil {
+		seen := make(map[string]bool, len(input.IDs))
+		ids := make([]string, 0, len(input.IDs))
+		for _, id := range input.IDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		return ids, nil
+	}
+
+	query := s.articles()
+	if input.Filter.Status != "" {
+		query = query.Where("status = ?", input.Filter.Status)
+	}
+	if input.Filter.ExpiresBefore != nil {
+		query = query.Where("expires_at IS NOT NULL AND expires_at < ?", *input.Filter.ExpiresBefore)
+	}
+	var ids []string
+	if err := query.Order("created_at ASC, id ASC").Limit(MaxBulkArticles+1).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) > MaxBulkArticles {
+		return nil, FieldErrors{"filter": fmt.Sprintf("filter matches more than %d articles", MaxBulkArticles)}
+	}
+	return ids, nil
+}
+
+// 处理单篇文章，按读取时的版本修改，期间被其他请求修改时返回 ErrEditConflict
+func (s *ArticleService) bulkApply(id string, input BulkInput) BulkResult {
+	result := BulkResult{ID: id}
+	article, err := s.GetArticleByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = notFoundf("article %s not found", id)
+		}
+		result.Err = err
+		return result
+	}
+
+	update := ArticleInput{ExpectedVersion: &article.Version}
+	switch input.Action {
+	case BulkDelete:
+		result.Err = s.DeleteArticle(id, &article.Version)
+		return result
+	case BulkPublish:
+		update.Status = "published"
+	case BulkUnpublish:
+		update.Status = "draft"
+	case BulkArchive:
+		update.Status = "archived"
+	case BulkExtend:
+		result.Article, result.Err = s.ExtendExpiry(id, input.ExtendBy, &article.Version)
+		return result
+	}
+
+	result.Article, result.Err = s.UpdateArticle(id, update)
+	return result
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"static-hosting-server/internal/config"
+	"static-hosting-server/internal/models"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DomainService struct {
+	db       *gorm.DB
+	cfg      *config.Config
+	notifier *Notifier
+	sites    *SiteService
+
+	mu    sync.RWMutex
+	certs map[string]*tls.Certificate
+}
+
+func NewDomainService(db *gorm.DB, cfg *config.Config) *DomainService {
+	return &DomainService{
+		db:       db,
+		cfg:      cfg,
+		notifier: NewNotifier(cfg),
+		certs:    make(map[string]*tls.Certificate),
+	}
+}
+
+// 添加自定义域名，并为其关联证书记录；siteID不为空时作为该站点的域名
+func (s *DomainService) CreateDomain(host string, siteID *uint) (*models.Domain, error) {
+	host = NormalizeHost(host)
+	if host == "" {
+		return nil, FieldErrors{"host": "host is required"}
+	}
+	if host == NormalizeHost(s.cfg.Server.Domain) {
+		return nil, conflictf("host '%s' is the default server domain", host)
+	}
+
+	var existing models.Domain
+	if err := s.db.Where("host = ?", host).First(&existing).Error; err == nil {
+		return nil, conflictf("domain '%s' already exists", host)
+	}
+
+	domain := &models.Domain{
+		Host:     host,
+		SiteID:   siteID,
+		IsActive: true,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		cert, err := s.ensureCertificate(tx, host)
+		if err != nil {
+			return err
+		}
+		domain.CertificateID = &cert.ID
+		return tx.Create(domain).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain, nil
+}
+
+// 获取证书记录，不存在时创建一条待签发的记录
+func (s *DomainService) ensureCertificate(tx *gorm.DB, host string) (*models.Certificate, error) {
+	var cert models.Certificate
+	if err := tx.Where("domain = ?", host).First(&cert).Error; err == nil {
+		return &cert, nil
+	}
+
+	certDir := filepath.Join(s.cfg.Storage.CertsPath, host)
+	cert = models.Certificate{
+		Domain:   host,
+		CertPath: filepath.Join(certDir, "fullchain.pem"),
+		KeyPath:  filepath.Join(certDir, "privkey.pem"),
+		// 尚未签发，视为已到期以便续期任务立即处理
+		ExpiresAt: time.Now(),
+		AutoRenew: true,
+	}
+	if err := tx.Create(&cert).Error; err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// 获取域名列表，siteID不为空时只返回该站点可用的域名
+func (s *DomainService) ListDomains(siteID *uint) ([]models.Domain, error) {
+	var domains []models.Domain
+	query := s.db.Order("host ASC")
+	if siteID != nil {
+		query = query.Where("site_id = ?", *siteID)
+	}
+	if err := query.Find(&domains).Error; err != nil {
+		return nil, err
+	}
+	return domains, nil
+}
+
+// 根据ID获取域名
+func (s *DomainService) GetDomainByID(id uint) (*models.Domain, error) {
+	var domain models.Domain
+	if err := s.db.First(&domain, id).Error; err != nil {
+		return nil, err
+	}
+	return &domain, nil
+}
+
+// 删除域名，已关联的文章回退到默认域名
+func (s *DomainService) DeleteDomain(id uint) error {
+	domain, err := s.GetDomainByID(id)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Article{}).Where("domain_id = ?", domain.ID).
+			Updates(map[string]interface{}{"domain_id": nil, "version": gorm.Expr("version + 1")}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(domain).Error
+	})
+}
+
+// 根据请求的Host查找启用的自定义域名，未匹配时返回nil
+func (s *DomainService) ResolveHost(host string) *models.Domain {
+	host = NormalizeHost(host)
+	if host == "" || host == NormalizeHost(s.cfg.Server.Domain) {
+		return nil
+	}
+
+	var domain models.Domain
+	if err := s.db.Where("host = ? AND is_active = ?", host, true).First(&domain).Error; err != nil {
+		return nil
+	}
+	return &domain
+}
+
+// 文章的规范访问地址
+func (s *DomainService) ArticleURL(article *models.Article) string {
+	return s.BaseURL(article) + ArticlePath(article.Slug)
+}
+
+// 文章的基础地址：优先使用文章绑定的域名，其次是站点域名；
+// 都没有时使用默认域名，非默认站点再加上 /s/<存储前缀>，与公开路由一致
+func (s *DomainService) BaseURL(article *models.Article) string {
+	if domain := s.ArticleDomain(article); domain != nil {
+		return s.domainBaseURL(domain)
+	}
+	return DefaultBaseURL(s.cfg) + s.sites.SitePath(article.SiteID)
+}
+
+// 文章所在的主机地址，不含站点路径，用于预览链接和补全站内资源地址
+func (s *DomainService) HostURL(article *models.Article) string {
+	if domain := s.ArticleDomain(article); domain != nil {
+		return s.domainBaseURL(domain)
+	}
+	return DefaultBaseURL(s.cfg)
+}
+
+// 能提供该文章的自定义域名，没有时返回nil：文章绑定的域名需要属于文章所在站点（默认站点的文章使用未绑定站点的域名）
+func (s *DomainService) ArticleDomain(article *models.Article) *models.Domain {
+	if article.DomainID != nil {
+		if domain, err := s.GetDomainByID(*article.DomainID); err == nil && domain.IsActive && domainServesSite(domain, article.SiteID) {
+			return domain
+		}
+	}
+	return s.SiteDomain(article.SiteID)
+}
+
+// 域名按Host路由到的站点是否为指定站点，未绑定站点的域名属于默认站点
+func domainServesSite(domain *models.Domain, siteID uint) bool {
+	if domain.SiteID == nil {
+		return siteID == 0
+	}
+	return *domain.SiteID == siteID
+}
+
+// 站点的主域名（最早绑定的启用域名），默认站点或未绑定时返回nil
+func (s *DomainService) SiteDomain(siteID uint) *models.Domain {
+	if siteID == 0 {
+		return nil
+	}
+
+	var domain models.Domain
+	if err := s.db.Where("site_id = ? AND is_active = ?", siteID, true).Order("id ASC").First(&domain).Error; err != nil {
+		return nil
+	}
+	return &domain
+}
+
+// 启用了HTTPS服务且域名的证书已签发并在有效期内时使用HTTPS，
+// 待签发的证书记录（没有证书文件）或已过期的证书仍使用HTTP
+func (s *DomainService) domainBaseURL(domain *models.Domain) string {
+	port := s.cfg.Server.HTTPSPort
+	if port == "" || domain.CertificateID == nil || !s.certificateValid(*domain.CertificateID) {
+		return "http://" + domain.Host
+	}
+	if port == "443" {
+		return "https://" + domain.Host
+	}
+	return "https://" + net.JoinHostPort(domain.Host, port)
+}
+
+// 证书文件和私钥都已写入，且证书尚未过期
+func (s *DomainService) certificateValid(id uint) bool {
+	var record models.Certificate
+	if err := s.db.First(&record, id).Error; err != nil {
+		return false
+	}
+	if _, err := os.Stat(record.KeyPath); err != nil {
+		return false
+	}
+	notAfter, err := certificateNotAfter(record.CertPath)
+	return err == nil && time.Now().Before(notAfter)
+}
+
+// 默认域名的基础地址
+func DefaultBaseURL(cfg *config.Config) string {
+	base := strings.TrimRight(cfg.Server.Domain, "/")
+	if strings.Contains(base, "://") {
+		return base
+	}
+	return "http://" + base
+}
+
+// 为TLS握手按SNI加载证书，供HTTPS服务使用
+func (s *DomainService) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	host := NormalizeHost(hello.ServerName)
+
+	var record models.Certificate
+	if err := s.db.Where("domain = ?", host).First(&record).Error; err != nil {
+		return nil, fmt.Errorf("no certificate for host '%s'", host)
+	}
+
+	cacheKey := host + "|" + record.UpdatedAt.String()
+	s.mu.RLock()
+	cert, ok := s.certs[cacheKey]
+	s.mu.RUnlock()
+	if ok {
+		return cert, nil
+	}
+
+	loaded, err := tls.LoadX509KeyPair(record.CertPath, record.KeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificate for host '%s': %w", host, err)
+	}
+
+	s.mu.Lock()
+	s.certs[cacheKey] = &loaded
+	s.mu.Unlock()
+
+	return &loaded, nil
+}
+
+// 证书到期前多少天开始提醒
+const certificateRenewBefore = 30 * 24 * time.Hour
+
+// 从证书文件读取到期时间更新证书记录，并对即将到期的自动续期证书发送提醒，本身不签发或续期证书。
+// 证书由外部ACME客户端签发并写入 certs_path，记录更新后HTTPS服务会重新加载证书。返回更新的数量
+func (s *DomainService) RefreshCertificates() (int, error) {
+	var records []models.Certificate
+	if err := s.db.Find(&records).Error; err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for i := range records {
+		record := &records[i]
+		notAfter, err := certificateNotAfter(record.CertPath)
+		if err != nil {
+			// 尚未签发的证书没有文件
+			if !os.IsNotExist(err) {
+				fmt.Printf("Failed to read certificate for %s: %v\n", record.Domain, err)
+			}
+		} else if !notAfter.Equal(record.ExpiresAt) {
+			if err := s.db.Model(record).Update("expires_at", notAfter).Error; err != nil {
+				return updated, err
+			}
+			updated++
+		}
+
+		if record.AutoRenew && time.Until(record.ExpiresAt) < certificateRenewBefore && s.notifier.Enabled() {
+			expiresAt := record.ExpiresAt.Format("2006-01-02 15:04")
+			err := s.notifier.Notify("certificate.expiring",
+				fmt.Sprintf("证书即将过期：%s", record.Domain),
+				fmt.Sprintf("域名 %s 的证书将于 %s 过期，请续期后写入 %s。", record.Domain, expiresAt, record.CertPath),
+				map[string]interface{}{
+					"domain":     record.Domain,
+					"expires_at": record.ExpiresAt,
+					"cert_path":  record.CertPath,
+				})
+			if err != nil {
+				fmt.Printf("Failed to send certificate warning for %s: %v\n", record.Domain, err)
+			}
+		}
+	}
+	return updated, nil
+}
+
+// 读取PEM证书文件中第一个证书的到期时间
+func certificateNotAfter(path string) (time.Time, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return time.Time{}, fmt.Errorf("no certificate found in %s", path)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
+
+// 规范化主机名：去掉协议、端口和末尾的点，并转为小写
+func NormalizeHost(host string) string {
+	host = strings.TrimSpace(strings.ToLower(host))
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	if i := strings.Index(host, "/"); i >= 0 {
+		host = host[:i]
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(host, ".")
+}
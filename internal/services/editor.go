@@ -0,0 +1,118 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"static-hosting-server/internal/config"
+	"static-hosting-server/internal/models"
+	"static-hosting-server/internal/theme"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 超过此时间未更新的自动保存草稿会被清理
+const draftRetention = 30 * 24 * time.Hour
+
+// ErrEditConflict 文章在开始编辑（或读取）后已被其他人修改，版本号不一致
+var ErrEditConflict = errors.New("article has been modified by someone else")
+
+// DraftService 管理编辑器自动保存的草稿
+type DraftService struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewDraftService(db *gorm.DB, cfg *config.Config) *DraftService {
+	return NewServices(db, cfg, theme.NewManager(cfg), nil).Drafts
+}
+
+// DraftInput 自动保存的内容，ArticleID 为空表示新建文章
+type DraftInput struct {
+	UserID      uint
+	SiteID      uint
+	ArticleID   string
+	Title       string
+	Content     string
+	BaseVersion int
+}
+
+// 保存草稿，同一管理员在同一篇文章上只保留最新的一份
+func (s *DraftService) SaveDraft(input DraftInput) (*models.ArticleDraft, error) {
+	draft := &models.ArticleDraft{
+		UserID:      input.UserID,
+		SiteID:      input.SiteID,
+		ArticleID:   input.ArticleID,
+		Title:       input.Title,
+		Content:     input.Content,
+		BaseVersion: input.BaseVersion,
+	}
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "site_id"}, {Name: "article_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "content", "base_version", "updated_at"}),
+	}).Create(draft).Error
+	if err != nil {
+		return nil, err
+	}
+
+	s.db.Where("updated_at < ?", time.Now().Add(-draftRetention)).Delete(&models.ArticleDraft{})
+	return draft, nil
+}
+
+// 获取草稿，没有草稿时返回 gorm.ErrRecordNotFound
+func (s *DraftService) GetDraft(userID, siteID uint, articleID string) (*models.ArticleDraft, error) {
+	var draft models.ArticleDraft
+	if err := s.db.Where("user_id = ? AND site_id = ? AND article_id = ?", userID, siteID, articleID).
+		First(&draft).Error; err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+// 删除草稿，文章保存成功或放弃草稿时调用
+func (s *DraftService) DeleteDraft(userID, siteID uint, articleID string) error {
+	return s.db.Where("user_id = ? AND site_id = ? AND article_id = ?", userID, siteID, articleID).
+		Delete(&models.ArticleDraft{}).Error
+}
+
+// 按编辑中的内容渲染预览，与生成静态文件使用同一主题和模板。
+// id不为空时以该文章为基础，只覆盖输入中不为空的字段；不保存任何修改
+func (s *ArticleService) PreviewArticle(w io.Writer, id string, input ArticleInput) error {
+	article := &models.Article{
+		SiteID:     s.currentSiteID(),
+		Status:     "draft",
+		Visibility: VisibilityPublic,
+	}
+	if id != "" {
+		existing, err := s.GetArticleByID(id)
+		if err != nil {
+			return err
+		}
+		article = existing
+	}
+
+	if input.Title != "" {
+		article.Title = input.Title
+	}
+	if input.Content != "" {
+		article.Content = input.Content
+	}
+	if input.Theme != "" {
+		if err := s.checkTheme(input.Theme); err != nil {
+			return err
+		}
+		article.Theme = input.Theme
+	}
+	if input.Slug != "" {
+		article.Slug = input.Slug
+	}
+	if err := input.Meta.apply(article); err != nil {
+		return err
+	}
+
+	return s.RenderArticle(w, article, map[string]interface{}{
+		"preview": true,
+		"noindex": true,
+	})
+}
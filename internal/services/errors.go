@@ -0,0 +1,66 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 服务返回的错误类别，调用方通过 errors.Is 判断并转换为对应的HTTP状态码。
+// 校验错误使用 FieldErrors，文章版本冲突使用 ErrEditConflict
+var (
+	// ErrNotFound 资源不存在，或不属于当前站点
+	ErrNotFound = errors.New("not found")
+	// ErrConflict 与已有数据冲突，如slug、名称重复，或仍被其他数据使用
+	ErrConflict = errors.New("conflict")
+)
+
+// 带类别的错误，Error 返回具体原因，Unwrap 返回类别
+type kindError struct {
+	kind    error
+	message string
+}
+
+func (e *kindError) Error() string { return e.message }
+func (e *kindError) Unwrap() error { return e.kind }
+
+// 资源不存在
+func notFoundf(format string, args ...interface{}) error {
+	return &kindError{kind: ErrNotFound, message: fmt.Sprintf(format, args...)}
+}
+
+// 与已有数据冲突
+func conflictf(format string, args ...interface{}) error {
+	return &kindError{kind: ErrConflict, message: fmt.Sprintf(format, args...)}
+}
+
+// FieldErrors 按字段的校验错误，键为请求中的JSON字段名
+type FieldErrors map[string]string
+
+func (e FieldErrors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		parts = append(parts, field+": "+e[field])
+	}
+	return "invalid fields: " + strings.Join(parts, "; ")
+}
+
+// 没有错误时返回nil，避免把空的 FieldErrors 当作错误返回
+func (e FieldErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// 单个字段的校验错误
+func fieldError(field string, err error) FieldErrors {
+	return FieldErrors{field: err.Error()}
+}
@@ -0,0 +1,319 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"static-hosting-server/internal/events"
+	"static-hosting-server/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 文章过期后的处理方式
+const (
+	ExpiryUnpublish = "unpublish" // 下线页面（默认）
+	ExpiryArchive   = "archive"   // 保留页面并显示“已归档”提示
+	ExpiryRedirect  = "redirect"  // 跳转到指定地址
+	ExpiryDelete    = "delete"    // 下线页面，宽限期后删除数据
+)
+
+// 过期处理方式的校验错误，跳转方式的错误归到 redirect_url 字段
+func expiryActionError(action string, err error) FieldErrors {
+	if action == ExpiryRedirect {
+		return fieldError("redirect_url", err)
+	}
+	return fieldError("expiry_action", err)
+}
+
+// 校验过期处理方式及跳转地址
+func validateExpiryAction(action, redirectURL string) error {
+	switch action {
+	case ExpiryUnpublish, ExpiryArchive, ExpiryDelete:
+		return nil
+	case ExpiryRedirect:
+		if redirectURL == "" {
+			return fmt.Errorf("redirect_url is required when expiry_action is redirect")
+		}
+		if !validLinkTarget(redirectURL) {
+			return fmt.Errorf("invalid redirect_url '%s'", redirectURL)
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid expiry_action '%s'", action)
+}
+
+// 文章是否已过期：已被清理任务处理，或已超过过期时间但尚未处理
+func IsExpired(article *models.Article) bool {
+	switch article.Status {
+	case "expired", "archived":
+		return true
+	}
+	return article.ExpiresAt != nil && article.ExpiresAt.Before(time.Now())
+}
+
+// 文章是否以归档形式继续展示：手动或批量归档的文章，以及过期策略为 archive 的已过期文章。
+// 访问路由和静态文件都按此判断，保证两者一致
+func IsArchived(article *models.Article) bool {
+	if article.Status == "archived" {
+		return true
+	}
+	return IsExpired(article) && article.ExpiryAction == ExpiryArchive
+}
+
+// 清理过期文章：发送即将过期提醒，按文章的过期策略处理已过期文章，并删除超过宽限期的文章。
+// 返回处理和删除的文章数量
+func (s *ArticleService) CleanupExpiredArticles() (int, error) {
+	if err := s.sendExpiryWarnings(); err != nil {
+		fmt.Printf("Failed to send expiry warnings: %v\n", err)
+	}
+
+	processed, err := s.ExpireDueArticles()
+	if err != nil {
+		return processed, err
+	}
+
+	purged, err := s.purgeExpiredArticles()
+	return processed + purged, err
+}
+
+// 按过期策略处理已到过期时间的已发布文章，返回处理的数量
+func (s *ArticleService) ExpireDueArticles() (int, error) {
+	var expiredArticles []models.Article
+	if err := s.articles().Where("expires_at IS NOT NULL AND expires_at <= ? AND status = ?",
+		time.Now(), "published").Find(&expiredArticles).Error; err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for i := range expiredArticles {
+		if err := s.expireArticle(&expiredArticles[i]); err != nil {
+			fmt.Printf("Failed to expire article %s: %v\n", expiredArticles[i].ID, err)
+			continue
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+// 已发布文章中最近的过期时间，after不为空时只看该时间之后的过期时间（跳过处理失败、等待重试的文章），
+// 没有待过期的文章时返回nil
+func (s *ArticleService) NextExpiry(after *time.Time) (*time.Time, error) {
+	query := s.articles().Select("expires_at").Where("expires_at IS NOT NULL AND status = ?", "published")
+	if after != nil {
+		query = query.Where("expires_at > ?", *after)
+	}
+	var article models.Article
+	err := query.Order("expires_at ASC").First(&article).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return article.ExpiresAt, nil
+}
+
+// 立即按过期策略处理文章，未设置过期时间或尚未到期时把过期时间改为当前时间
+func (s *ArticleService) ExpireArticle(id string) (*models.Article, error) {
+	article, err := s.GetArticleByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if article.ExpiresAt == nil || article.ExpiresAt.After(now) {
+		if err := s.db.Model(article).Updates(map[string]interface{}{
+			"expires_at": now,
+			"version":    gorm.Expr("version + 1"),
+		}).Error; err != nil {
+			return nil, err
+		}
+	}
+	if err := s.expireArticle(article); err != nil {
+		return nil, err
+	}
+	return s.GetArticleByID(id)
+}
+
+// 文章是否已被过期处理：状态为 expired，或过期后以归档形式保留。这类文章需要用 ReviveArticle 恢复
+func expiryProcessed(article *models.Article) bool {
+	return article.Status == "expired" ||
+		(article.Status == "archived" && article.ExpiresAt != nil && !article.ExpiresAt.After(time.Now()))
+}
+
+// 读取要修改过期时间的文章，version不为空时检查版本
+func (s *ArticleService) expiryTarget(id string, version *int) (*models.Article, error) {
+	article, err := s.GetArticleByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if version != nil && *version != article.Version {
+		return nil, ErrEditConflict
+	}
+	return article, nil
+}
+
+// 将过期时间延长 by：从原过期时间开始计算，已超过过期时间但尚未处理的从当前时间开始计算。
+// 未设置过期时间的文章返回 FieldErrors，已被过期处理的文章返回 ErrConflict
+func (s *ArticleService) ExtendExpiry(id string, by time.Duration, version *int) (*models.Article, error) {
+	if by <= 0 {
+		return nil, FieldErrors{"extend_hours": "extend_hours must be positive"}
+	}
+	article, err := s.expiryTarget(id, version)
+	if err != nil {
+		return nil, err
+	}
+	if article.ExpiresAt == nil {
+		return nil, FieldErrors{"expires_at": "article does not expire"}
+	}
+	if expiryProcessed(article) {
+		return nil, conflictf("article %s has already expired, revive it instead", id)
+	}
+
+	base := time.Now()
+	if article.ExpiresAt.After(base) {
+		base = *article.ExpiresAt
+	}
+	expiresAt := base.Add(by)
+	return s.UpdateArticle(id, ArticleInput{ExpiresAt: &expiresAt, ExpectedVersion: &article.Version})
+}
+
+// 取消文章的过期时间，未设置过期时间时不做修改。已被过期处理的文章返回 ErrConflict
+func (s *ArticleService) MakePermanent(id string, version *int) (*models.Article, error) {
+	article, err := s.expiryTarget(id, version)
+	if err != nil {
+		return nil, err
+	}
+	if expiryProcessed(article) {
+		return nil, conflictf("article %s has already expired, revive it instead", id)
+	}
+	if article.ExpiresAt == nil {
+		return article, nil
+	}
+	return s.UpdateArticle(id, ArticleInput{Clear: []string{"expires_at"}, ExpectedVersion: &article.Version})
+}
+
+// 恢复已过期的文章：重新发布并重新生成静态文件，过期时间改为 expiresAt，为空时不再过期。
+// 文章尚未过期或为草稿时返回 ErrConflict
+func (s *ArticleService) ReviveArticle(id string, expiresAt *time.Time, version *int) (*models.Article, error) {
+	article, err := s.expiryTarget(id, version)
+	if err != nil {
+		return nil, err
+	}
+	if article.Status == "draft" || !IsExpired(article) {
+		return nil, conflictf("article %s has not expired", id)
+	}
+
+	input := ArticleInput{Status: "published", ExpectedVersion: &article.Version}
+	if expiresAt != nil {
+		input.ExpiresAt = expiresAt
+	} else {
+		input.Clear = []string{"expires_at"}
+	}
+	return s.UpdateArticle(id, input)
+}
+
+// 按过期策略处理单篇文章，处理成功后发布过期事件
+func (s *ArticleService) expireArticle(article *models.Article) error {
+	if err := s.applyExpiryAction(article); err != nil {
+		return err
+	}
+	s.publish(events.ArticleExpired, article)
+	return nil
+}
+
+// 归档的文章保留页面，其余改为过期状态，静态文件由任务按新状态重新输出
+func (s *ArticleService) applyExpiryAction(article *models.Article) error {
+	status := "expired"
+	if article.ExpiryAction == ExpiryArchive {
+		status = "archived"
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(article).Updates(map[string]interface{}{
+			"status":  status,
+			"version": gorm.Expr("version + 1"),
+		}).Error; err != nil {
+			return err
+		}
+		return s.enqueueRender(tx, article)
+	})
+}
+
+// 删除过期超过宽限期、且过期策略为 delete 的文章
+func (s *ArticleService) purgeExpiredArticles() (int, error) {
+	cutoff := time.Now().Add(-s.cfg.Expiry.DeleteGracePeriod)
+
+	var articles []models.Article
+	if err := s.articles().Where("status = ? AND expiry_action = ? AND expires_at < ?",
+		"expired", ExpiryDelete, cutoff).Find(&articles).Error; err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for i := range articles {
+		article := &articles[i]
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("article_id = ?", article.ID).Delete(&models.PreviewToken{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Delete(article).Error; err != nil {
+				return err
+			}
+			return s.enqueueRemove(tx, article)
+		})
+		if err != nil {
+			fmt.Printf("Failed to delete expired article %s: %v\n", article.ID, err)
+			continue
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// 向即将在 WarningDays 天内过期的文章发送一次提醒
+func (s *ArticleService) sendExpiryWarnings() error {
+	if s.cfg.Expiry.WarningDays <= 0 || !s.notifier.Enabled() {
+		return nil
+	}
+
+	now := time.Now()
+	deadline := now.AddDate(0, 0, s.cfg.Expiry.WarningDays)
+
+	var articles []models.Article
+	if err := s.articles().Where("status = ? AND warned_at IS NULL AND expires_at IS NOT NULL AND expires_at > ? AND expires_at <= ?",
+		"published", now, deadline).Find(&articles).Error; err != nil {
+		return err
+	}
+
+	for i := range articles {
+		article := &articles[i]
+		articleURL := s.PublicURL(article)
+		expiresAt := article.ExpiresAt.Format("2006-01-02 15:04")
+
+		err := s.notifier.Notify("article.expiring",
+			fmt.Sprintf("文章即将过期：%s", article.Title),
+			fmt.Sprintf("文章《%s》将于 %s 过期，过期后将执行：%s。\n\n%s", article.Title, expiresAt, article.ExpiryAction, articleURL),
+			map[string]interface{}{
+				"id":            article.ID,
+				"title":         article.Title,
+				"slug":          article.Slug,
+				"site_id":       article.SiteID,
+				"url":           articleURL,
+				"expires_at":    article.ExpiresAt,
+				"expiry_action": article.ExpiryAction,
+			})
+		if err != nil {
+			fmt.Printf("Failed to send expiry warning for article %s: %v\n", article.ID, err)
+			continue
+		}
+
+		if err := s.db.Model(article).Update("warned_at", &now).Error; err != nil {
+			fmt.Printf("Failed to mark expiry warning for article %s: %v\n", article.ID, err)
+		}
+	}
+
+	return nil
+}
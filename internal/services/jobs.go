@@ -0,0 +1,121 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"static-hosting-server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// 文章静态文件的后台任务类型
+const (
+	JobRenderArticle = "article.render" // 按文章当前状态生成、替换或删除静态文件
+	JobRemoveArticle = "article.remove" // 删除已删除文章的静态文件
+)
+
+// 文章任务的参数，删除任务执行时文章已不存在，因此同时记录站点和slug
+type articleJob struct {
+	ArticleID string `json:"article_id"`
+	SiteID    uint   `json:"site_id"`
+	Slug      string `json:"slug"`
+}
+
+func (s *ArticleService) registerJobs() {
+	s.queue.Register(JobRenderArticle, s.runRenderJob)
+	s.queue.Register(JobRemoveArticle, s.runRemoveJob)
+}
+
+// 在tx中添加静态文件生成任务，与文章状态的修改一起提交
+func (s *ArticleService) enqueueRender(tx *gorm.DB, article *models.Article) error {
+	return s.queue.Enqueue(tx, JobRenderArticle, article.SiteID, articleJob{
+		ArticleID: article.ID,
+		SiteID:    article.SiteID,
+		Slug:      article.Slug,
+	})
+}
+
+// 为当前站点范围内使用站点主题的已发布和归档文章添加静态文件生成任务，返回加入队列的数量。
+// 用于修改站点主题后在后台重新生成页面
+func (s *ArticleService) EnqueueRebuild() (int, error) {
+	var articles []models.Article
+	if err := s.articles().Where("status IN ? AND theme = ?", []string{"published", "archived"}, "").
+		Find(&articles).Error; err != nil {
+		return 0, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for i := range articles {
+			if err := s.enqueueRender(tx, &articles[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(articles), nil
+}
+
+// 在tx中添加静态文件删除任务，与文章的删除一起提交
+func (s *ArticleService) enqueueRemove(tx *gorm.DB, article *models.Article) error {
+	return s.queue.Enqueue(tx, JobRemoveArticle, article.SiteID, articleJob{
+		ArticleID: article.ID,
+		SiteID:    article.SiteID,
+		Slug:      article.Slug,
+	})
+}
+
+// 任务执行时文章可能已再次修改，总是按数据库中的最新状态输出
+func (s *ArticleService) runRenderJob(payload []byte) error {
+	var job articleJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return err
+	}
+
+	var article models.Article
+	err := s.db.Where("id = ?", job.ArticleID).First(&article).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// 文章已删除，静态文件由删除任务处理
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.syncStaticFiles(&article)
+}
+
+func (s *ArticleService) runRemoveJob(payload []byte) error {
+	var job articleJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return err
+	}
+	if job.Slug == "" {
+		return nil
+	}
+
+	// 路径已被其他文章或跳转重新占用、或文章已从回收站恢复时改为输出它们的内容
+	var other models.Article
+	if err := s.db.Where("site_id = ? AND slug = ?", job.SiteID, job.Slug).First(&other).Error; err == nil {
+		return s.syncStaticFiles(&other)
+	}
+	if redirect, err := s.redirects.findRedirect(job.SiteID, articlePathPrefix+job.Slug); err == nil {
+		return s.redirects.writeStub(redirect)
+	}
+
+	return os.RemoveAll(s.staticDir(job.SiteID, job.Slug))
+}
+
+// 按文章状态输出静态文件：已发布和归档的文章生成页面，过期跳转的文章写入跳转页，其余删除
+func (s *ArticleService) syncStaticFiles(article *models.Article) error {
+	switch {
+	case article.Status == "published" || article.Status == "archived":
+		return s.generateStaticFiles(article)
+	case article.Status == "expired" && article.ExpiryAction == ExpiryRedirect:
+		return writeRedirectStub(s.articleDir(article), article.RedirectURL)
+	default:
+		return s.removeStaticFiles(article)
+	}
+}
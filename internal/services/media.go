@@ -0,0 +1,142 @@
+package services
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"static-hosting-server/internal/models"
+	"time"
+)
+
+// 上传后多久仍未被引用的文件视为孤立文件，留出编辑中尚未保存的时间
+const orphanMediaGrace = 24 * time.Hour
+
+// 上传文件的访问路径前缀和单个文件的大小上限
+const (
+	UploadsURLPrefix = "/uploads/"
+	MaxUploadSize    = 10 << 20
+)
+
+// 允许上传的图片类型及保存时使用的扩展名。SVG可以包含脚本，不允许上传
+var uploadExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// 保存编辑器中粘贴或选择的图片，按内容识别类型，返回站内访问地址（/uploads/...）。
+// 文件保存在当前站点的存储前缀和年月目录下，文件名随机生成
+func (s *ArticleService) SaveUpload(r io.Reader) (string, error) {
+	root := s.cfg.Storage.UploadsPath
+	if root == "" {
+		return "", fmt.Errorf("uploads are not configured")
+	}
+
+	reader := bufio.NewReader(io.LimitReader(r, MaxUploadSize+1))
+	head, _ := reader.Peek(512)
+	ext, ok := uploadExtensions[http.DetectContentType(head)]
+	if !ok {
+		return "", fmt.Errorf("unsupported file type, only PNG, JPEG, GIF and WebP images are allowed")
+	}
+
+	name := make([]byte, 16)
+	if _, err := rand.Read(name); err != nil {
+		return "", err
+	}
+	rel := path.Join(s.sites.StoragePrefix(s.currentSiteID()), time.Now().Format("2006/01"), hex.EncodeToString(name)+ext)
+	target := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", fmt.Errorf("failed to create upload directory: %w", err)
+	}
+
+	file, err := os.Create(target)
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload: %w", err)
+	}
+	written, err := io.Copy(file, reader)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && written > MaxUploadSize {
+		err = fmt.Errorf("file exceeds the %d MB limit", MaxUploadSize>>20)
+	}
+	if err != nil {
+		os.Remove(target)
+		return "", err
+	}
+	return UploadsURLPrefix + rel, nil
+}
+
+// 删除上传目录中没有被任何文章（包括已删除的文章）、文章模板或草稿引用的文件，返回删除的数量
+func (s *ArticleService) CleanupOrphanMedia() (int, error) {
+	root := s.cfg.Storage.UploadsPath
+	if root == "" {
+		return 0, nil
+	}
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	cutoff := time.Now().Add(-orphanMediaGrace)
+	removed := 0
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		referenced, err := s.mediaReferenced(filepath.ToSlash(rel))
+		if err != nil || referenced {
+			return err
+		}
+
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
+
+// 文章内容、封面图、文章模板或自动保存的草稿中是否引用了上传目录中的文件
+func (s *ArticleService) mediaReferenced(name string) (bool, error) {
+	pattern := "%" + name + "%"
+
+	var count int64
+	if err := s.db.Unscoped().Model(&models.Article{}).
+		Where("content LIKE ? OR cover_image LIKE ?", pattern, pattern).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	if err := s.db.Model(&models.Blueprint{}).Where("content LIKE ?", pattern).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	// 编辑器中粘贴后尚未保存的图片
+	if err := s.db.Model(&models.ArticleDraft{}).Where("content LIKE ?", pattern).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/smtp"
+	"static-hosting-server/internal/config"
+	"strings"
+	"time"
+)
+
+// Notifier 通过Webhook和邮件发送事件通知
+type Notifier struct {
+	cfg    *config.Config
+	client *http.Client
+}
+
+func NewNotifier(cfg *config.Config) *Notifier {
+	return &Notifier{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// 是否配置了任一通知渠道
+func (n *Notifier) Enabled() bool {
+	return n.cfg.Notify.WebhookURL != "" || n.cfg.Notify.SMTP.Host != ""
+}
+
+// 发送通知，subject和body用于邮件，payload以JSON发送到Webhook
+func (n *Notifier) Notify(event, subject, body string, payload map[string]interface{}) error {
+	var errs []string
+
+	if n.cfg.Notify.WebhookURL != "" {
+		if err := n.postWebhook(event, payload); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if n.cfg.Notify.SMTP.Host != "" {
+		if err := n.sendMail(subject, body); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to send %s notification: %s", event, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// 以JSON POST事件到Webhook
+func (n *Notifier) postWebhook(event string, payload map[string]interface{}) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"event":     event,
+		"data":      payload,
+		"timestamp": time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client.Post(n.cfg.Notify.WebhookURL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// 通过SMTP发送纯文本邮件
+func (n *Notifier) sendMail(subject, body string) error {
+	smtpCfg := n.cfg.Notify.SMTP
+	if len(smtpCfg.To) == 0 {
+		return fmt.Errorf("smtp: no recipients configured")
+	}
+
+	var auth smtp.Auth
+	if smtpCfg.Username != "" {
+		auth = smtp.PlainAuth("", smtpCfg.Username, smtpCfg.Password, smtpCfg.Host)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + smtpCfg.From,
+		"To: " + strings.Join(smtpCfg.To, ", "),
+		"Subject: " + mime.QEncoding.Encode("UTF-8", subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	addr := fmt.Sprintf("%s:%d", smtpCfg.Host, smtpCfg.Port)
+	if err := smtp.SendMail(addr, auth, smtpCfg.From, smtpCfg.To, []byte(msg)); err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"static-hosting-server/internal/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 列表每页数量
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// 可以通过 fields 参数选择的文章字段，JSON名称与数据库列名相同
+var articleFields = []string{
+	"id", "title", "content", "site_id", "slug", "status", "expires_at", "domain_id", "theme",
+	"visibility", "expiry_action", "redirect_url", "warned_at", "description", "canonical_url",
+	"cover_image", "author", "og_title", "twitter_card", "version", "created_at", "updated_at",
+}
+
+// ArticleListOptions 文章列表的查询条件
+type ArticleListOptions struct {
+	Status string
+	Limit  int      // 超出范围时使用默认值或上限
+	Fields []string // 只返回的字段，为空时返回全部字段
+}
+
+// ErrInvalidCursor 游标无法解析
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// 游标指向上一页最后一篇文章，按 (created_at, id) 倒序翻页
+type articleCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+}
+
+// 编码为不透明的游标字符串
+func encodeArticleCursor(article *models.Article) string {
+	raw, _ := json.Marshal(articleCursor{CreatedAt: article.CreatedAt, ID: article.ID})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeArticleCursor(value string) (*articleCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor articleCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// ParseArticleFields 解析逗号分隔的字段列表，包含未知字段时返回错误
+func ParseArticleFields(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	known := make(map[string]bool, len(articleFields))
+	for _, field := range articleFields {
+		known[field] = true
+	}
+
+	var fields []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || seen[field] {
+			continue
+		}
+		if !known[field] {
+			return nil, fmt.Errorf("unknown field '%s'", field)
+		}
+		seen[field] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// 限制每页数量
+func (o ArticleListOptions) limit() int {
+	switch {
+	case o.Limit <= 0:
+		return DefaultPageSize
+	case o.Limit > MaxPageSize:
+		return MaxPageSize
+	}
+	return o.Limit
+}
+
+// 按状态过滤并只查询需要的列，翻页依赖的 id 和 created_at 总是查询
+func (s *ArticleService) listQuery(opts ArticleListOptions) *gorm.DB {
+	query := s.articles()
+	if opts.Status != "" {
+		query = query.Where("status = ?", opts.Status)
+	}
+	if len(opts.Fields) > 0 {
+		columns := []string{"id", "created_at"}
+		for _, field := range opts.Fields {
+			if field != "id" && field != "created_at" {
+				columns = append(columns, field)
+			}
+		}
+		query = query.Select(columns)
+	}
+	return query
+}
+
+// 按游标获取下一页文章，cursor为空时从最新的文章开始，返回下一页的游标（没有更多文章时为空）
+func (s *ArticleService) ListArticlesAfter(cursor string, opts ArticleListOptions) ([]models.Article, string, error) {
+	limit := opts.limit()
+	query := s.listQuery(opts)
+
+	if cursor != "" {
+		after, err := decodeArticleCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query = query.Where("created_at < ? OR (created_at = ? AND id < ?)", after.CreatedAt, after.CreatedAt, after.ID)
+	}
+
+	// 多取一篇判断是否还有下一页
+	var articles []models.Article
+	if err := query.Order("created_at DESC, id DESC").Limit(limit + 1).Find(&articles).Error; err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(articles) > limit {
+		articles = articles[:limit]
+		next = encodeArticleCursor(&articles[limit-1])
+	}
+	return articles, next, nil
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"static-hosting-server/internal/config"
+	"static-hosting-server/internal/models"
+	"static-hosting-server/internal/theme"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 预览链接默认和最长有效期
+const (
+	DefaultPreviewTTL = 24 * time.Hour
+	MaxPreviewTTL     = 30 * 24 * time.Hour
+)
+
+type PreviewService struct {
+	db      *gorm.DB
+	cfg     *config.Config
+	domains *DomainService
+}
+
+func NewPreviewService(db *gorm.DB, cfg *config.Config) *PreviewService {
+	return NewServices(db, cfg, theme.NewManager(cfg), nil).Previews
+}
+
+// 为文章创建预览链接，ttl为0时使用默认有效期
+func (s *PreviewService) CreatePreview(article *models.Article, ttl time.Duration) (*models.PreviewToken, error) {
+	if ttl <= 0 {
+		ttl = DefaultPreviewTTL
+	}
+	if ttl > MaxPreviewTTL {
+		return nil, FieldErrors{"ttl_hours": fmt.Sprintf("preview ttl must not exceed %s", MaxPreviewTTL)}
+	}
+
+	preview := &models.PreviewToken{
+		ArticleID: article.ID,
+		// 按秒截断，与令牌中的过期时间保持一致
+		ExpiresAt: time.Now().Add(ttl).Truncate(time.Second),
+	}
+	if err := s.db.Create(preview).Error; err != nil {
+		return nil, err
+	}
+	return preview, nil
+}
+
+// 获取文章的预览链接
+func (s *PreviewService) ListPreviews(articleID string) ([]models.PreviewToken, error) {
+	var previews []models.PreviewToken
+	if err := s.db.Where("article_id = ?", articleID).Order("created_at DESC").Find(&previews).Error; err != nil {
+		return nil, err
+	}
+	return previews, nil
+}
+
+// 根据ID获取预览链接
+func (s *PreviewService) GetPreviewByID(id uint) (*models.PreviewToken, error) {
+	var preview models.PreviewToken
+	if err := s.db.First(&preview, id).Error; err != nil {
+		return nil, err
+	}
+	return &preview, nil
+}
+
+// 撤销预览链接
+func (s *PreviewService) RevokePreview(id uint) error {
+	preview, err := s.GetPreviewByID(id)
+	if err != nil {
+		return err
+	}
+	if preview.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	return s.db.Model(preview).Update("revoked_at", &now).Error
+}
+
+// 预览链接的访问令牌，格式为 ID.过期时间.签名
+func (s *PreviewService) Token(preview *models.PreviewToken) string {
+	payload := fmt.Sprintf("%d.%d", preview.ID, preview.ExpiresAt.Unix())
+	return payload + "." + s.sign(payload, preview.ArticleID)
+}
+
+// 预览链接的完整地址
+func (s *PreviewService) URL(preview *models.PreviewToken, article *models.Article) string {
+	return s.domains.HostURL(article) + "/preview/" + s.Token(preview)
+}
+
+// 校验令牌并返回对应的文章（不限制文章状态）
+func (s *PreviewService) ResolveToken(token string) (*models.Article, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed preview token")
+	}
+
+	id, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("malformed preview token")
+	}
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("malformed preview token")
+	}
+
+	preview, err := s.GetPreviewByID(uint(id))
+	if err != nil {
+		return nil, fmt.Errorf("preview not found")
+	}
+
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(s.sign(payload, preview.ArticleID))) {
+		return nil, fmt.Errorf("invalid preview signature")
+	}
+	if preview.RevokedAt != nil {
+		return nil, fmt.Errorf("preview has been revoked")
+	}
+	if preview.ExpiresAt.Unix() != expiresAt || time.Now().After(preview.ExpiresAt) {
+		return nil, fmt.Errorf("preview has expired")
+	}
+
+	var article models.Article
+	if err := s.db.Where("id = ?", preview.ArticleID).First(&article).Error; err != nil {
+		return nil, fmt.Errorf("article not found")
+	}
+	return &article, nil
+}
+
+// 使用JWT密钥签名，签名中包含文章ID防止令牌被挪用
+func (s *PreviewService) sign(payload, articleID string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.Security.JWTSecret))
+	mac.Write([]byte("preview:" + articleID + ":" + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"static-hosting-server/internal/config"
+	"static-hosting-server/internal/models"
+	"static-hosting-server/internal/theme"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// 文章访问路径前缀
+const articlePathPrefix = "/p/"
+
+type RedirectService struct {
+	db    *gorm.DB
+	cfg   *config.Config
+	sites *SiteService
+}
+
+func NewRedirectService(db *gorm.DB, cfg *config.Config) *RedirectService {
+	return NewServices(db, cfg, theme.NewManager(cfg), nil).Redirects
+}
+
+// 文章的站内访问路径（已编码，用于链接和跳转目标）
+func ArticlePath(slug string) string {
+	return articlePathPrefix + url.PathEscape(slug)
+}
+
+// 链接地址（跳转目标、规范地址等）必须是站内路径或 http(s) 地址
+func validLinkTarget(target string) bool {
+	if strings.HasPrefix(target, "/") {
+		return !strings.HasPrefix(target, "//")
+	}
+	parsed, err := url.Parse(target)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// 规范化来源路径：必须以 / 开头，去掉查询参数和末尾的 /
+func normalizeSourcePath(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") {
+		return "", fmt.Errorf("invalid source path '%s'", path)
+	}
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	// 与请求中解码后的路径比较
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		path = unescaped
+	}
+	if len(path) > 1 {
+		path = strings.TrimRight(path, "/")
+	}
+	return path, nil
+}
+
+// 创建跳转，statusCode为0时使用301
+func (s *RedirectService) CreateRedirect(siteID uint, sourcePath, target string, statusCode int) (*models.Redirect, error) {
+	sourcePath, err := normalizeSourcePath(sourcePath)
+	if err != nil {
+		return nil, fieldError("source_path", err)
+	}
+	if !validLinkTarget(target) {
+		return nil, FieldErrors{"target": fmt.Sprintf("invalid redirect target '%s'", target)}
+	}
+	if target == sourcePath {
+		return nil, FieldErrors{"target": "redirect target must differ from source path"}
+	}
+	if statusCode == 0 {
+		statusCode = http.StatusMovedPermanently
+	}
+	if !validRedirectStatus(statusCode) {
+		return nil, FieldErrors{"status_code": fmt.Sprintf("invalid redirect status code %d", statusCode)}
+	}
+
+	if err := s.sites.CheckSiteID(siteID); err != nil {
+		return nil, err
+	}
+
+	// 已有文章占用的路径不能再设置跳转
+	if slug, ok := articleSlugFromPath(sourcePath); ok {
+		var count int64
+		s.db.Model(&models.Article{}).Where("site_id = ? AND slug = ?", siteID, slug).Count(&count)
+		if count > 0 {
+			return nil, conflictf("source path '%s' is used by an existing article", sourcePath)
+		}
+	}
+
+	var existing models.Redirect
+	if err := s.db.Where("site_id = ? AND source_path = ?", siteID, sourcePath).First(&existing).Error; err == nil {
+		return nil, conflictf("redirect from '%s' already exists", sourcePath)
+	}
+
+	redirect := &models.Redirect{
+		SiteID:     siteID,
+		SourcePath: sourcePath,
+		Target:     target,
+		StatusCode: statusCode,
+	}
+	if err := s.db.Create(redirect).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.writeStub(redirect); err != nil {
+		fmt.Printf("Failed to write redirect stub for %s: %v\n", redirect.SourcePath, err)
+	}
+	return redirect, nil
+}
+
+// 获取跳转列表，siteID为空时返回所有站点的跳转
+func (s *RedirectService) ListRedirects(siteID *uint) ([]models.Redirect, error) {
+	query := s.db.Order("source_path")
+	if siteID != nil {
+		query = query.Where("site_id = ?", *siteID)
+	}
+
+	var redirects []models.Redirect
+	if err := query.Find(&redirects).Error; err != nil {
+		return nil, err
+	}
+	return redirects, nil
+}
+
+// 根据ID获取跳转
+func (s *RedirectService) GetRedirectByID(id uint) (*models.Redirect, error) {
+	var redirect models.Redirect
+	if err := s.db.First(&redirect, id).Error; err != nil {
+		return nil, err
+	}
+	return &redirect, nil
+}
+
+// 删除跳转及其静态跳转页
+func (s *RedirectService) DeleteRedirect(id uint) error {
+	redirect, err := s.GetRedirectByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Delete(redirect).Error; err != nil {
+		return err
+	}
+	s.removeStub(redirect)
+	return nil
+}
+
+// 查找站点内匹配路径的跳转并记录访问次数
+func (s *RedirectService) Resolve(siteID uint, path string) (*models.Redirect, bool) {
+	sourcePath, err := normalizeSourcePath(path)
+	if err != nil {
+		return nil, false
+	}
+
+	redirect, err := s.findRedirect(siteID, sourcePath)
+	if err != nil {
+		return nil, false
+	}
+
+	s.db.Model(redirect).UpdateColumn("hit_count", gorm.Expr("hit_count + ?", 1))
+	return redirect, true
+}
+
+// 按规范化后的来源路径查找跳转
+func (s *RedirectService) findRedirect(siteID uint, sourcePath string) (*models.Redirect, error) {
+	var redirect models.Redirect
+	if err := s.db.Where("site_id = ? AND source_path = ?", siteID, sourcePath).First(&redirect).Error; err != nil {
+		return nil, err
+	}
+	return &redirect, nil
+}
+
+// 在tx中记录文章改名：旧路径301到新路径，并把指向旧路径的跳转直接指向新路径，避免跳转链。
+// 返回需要重新写入静态跳转页的slug，由调用方与文章修改一起加入任务队列
+func (s *RedirectService) recordSlugChange(tx *gorm.DB, siteID uint, oldSlug, newSlug string) ([]string, error) {
+	oldPath, newPath := articlePathPrefix+oldSlug, ArticlePath(newSlug)
+
+	// 新路径已被文章占用，之前的跳转不再需要
+	if err := s.releasePath(tx, siteID, articlePathPrefix+newSlug); err != nil {
+		return nil, err
+	}
+
+	var chained []models.Redirect
+	if err := tx.Where("site_id = ? AND target = ?", siteID, ArticlePath(oldSlug)).Find(&chained).Error; err != nil {
+		return nil, err
+	}
+	stubs := []string{oldSlug}
+	for i := range chained {
+		if err := tx.Model(&chained[i]).Update("target", newPath).Error; err != nil {
+			return nil, err
+		}
+		if slug, ok := articleSlugFromPath(chained[i].SourcePath); ok {
+			stubs = append(stubs, slug)
+		}
+	}
+
+	redirect := &models.Redirect{
+		SiteID:     siteID,
+		SourcePath: oldPath,
+		Target:     newPath,
+		StatusCode: http.StatusMovedPermanently,
+	}
+	if err := tx.Create(redirect).Error; err != nil {
+		return nil, err
+	}
+	return stubs, nil
+}
+
+// 删除来源为path的跳转（文章重新占用该路径时调用），静态文件由文章重新生成
+func (s *RedirectService) releasePath(tx *gorm.DB, siteID uint, path string) error {
+	return tx.Where("site_id = ? AND source_path = ?", siteID, path).Delete(&models.Redirect{}).Error
+}
+
+// 为 /p/<slug> 形式的跳转在静态输出中写入跳转页
+func (s *RedirectService) writeStub(redirect *models.Redirect) error {
+	dir, ok := s.stubDir(redirect)
+	if !ok {
+		return nil
+	}
+	return writeRedirectStub(dir, redirect.Target)
+}
+
+// 删除静态跳转页
+func (s *RedirectService) removeStub(redirect *models.Redirect) {
+	if dir, ok := s.stubDir(redirect); ok {
+		os.RemoveAll(dir)
+	}
+}
+
+// 跳转对应的静态文件目录，与文章静态文件位置一致
+func (s *RedirectService) stubDir(redirect *models.Redirect) (string, bool) {
+	slug, ok := articleSlugFromPath(redirect.SourcePath)
+	if !ok {
+		return "", false
+	}
+	return filepath.Join(s.cfg.Storage.StaticPath, s.sites.StoragePrefix(redirect.SiteID), "articles", slug), true
+}
+
+// 从 /p/<slug> 中取出slug
+func articleSlugFromPath(path string) (string, bool) {
+	if !strings.HasPrefix(path, articlePathPrefix) {
+		return "", false
+	}
+	slug := strings.TrimPrefix(path, articlePathPrefix)
+	if slug == "" || strings.Contains(slug, "/") || slug == "." || slug == ".." {
+		return "", false
+	}
+	return slug, true
+}
+
+// 支持的跳转状态码
+func validRedirectStatus(code int) bool {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"static-hosting-server/internal/models"
+	"static-hosting-server/internal/theme"
+	"strings"
+	"time"
+)
+
+// Twitter Card 类型
+const (
+	TwitterCardSummary      = "summary"
+	TwitterCardSummaryLarge = "summary_large_image"
+)
+
+// 自动摘要的最大字符数
+const excerptLength = 160
+
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// ArticleMetaInput 文章的SEO和分享卡片字段，为nil的字段不修改，空字符串表示清除
+type ArticleMetaInput struct {
+	Description  *string
+	CanonicalURL *string
+	CoverImage   *string
+	Author       *string
+	OGTitle      *string
+	TwitterCard  *string
+}
+
+// 校验并转换为要更新的字段
+func (m ArticleMetaInput) updates() (map[string]interface{}, error) {
+	updates := make(map[string]interface{})
+	for column, value := range map[string]*string{
+		"description":   m.Description,
+		"canonical_url": m.CanonicalURL,
+		"cover_image":   m.CoverImage,
+		"author":        m.Author,
+		"og_title":      m.OGTitle,
+		"twitter_card":  m.TwitterCard,
+	} {
+		if value == nil {
+			continue
+		}
+		v := strings.TrimSpace(*value)
+		switch column {
+		case "canonical_url", "cover_image":
+			if v != "" && !validLinkTarget(v) {
+				return nil, FieldErrors{column: fmt.Sprintf("invalid %s '%s'", column, v)}
+			}
+		case "twitter_card":
+			if v != "" && v != TwitterCardSummary && v != TwitterCardSummaryLarge {
+				return nil, FieldErrors{column: fmt.Sprintf("invalid twitter_card '%s'", v)}
+			}
+		}
+		updates[column] = v
+	}
+	return updates, nil
+}
+
+// 写入新建文章
+func (m ArticleMetaInput) apply(article *models.Article) error {
+	if _, err := m.updates(); err != nil {
+		return err
+	}
+
+	set := func(field *string, value *string) {
+		if value != nil {
+			*field = strings.TrimSpace(*value)
+		}
+	}
+	set(&article.Description, m.Description)
+	set(&article.CanonicalURL, m.CanonicalURL)
+	set(&article.CoverImage, m.CoverImage)
+	set(&article.Author, m.Author)
+	set(&article.OGTitle, m.OGTitle)
+	set(&article.TwitterCard, m.TwitterCard)
+	return nil
+}
+
+// 生成文章页面的SEO、分享卡片和JSON-LD数据
+func (s *ArticleService) articleMeta(article *models.Article, site *models.Site) theme.Meta {
+	// 规范链接是站内路径，封面图等资源在主机根路径下（如 /uploads）
+	absolute := func(baseURL, link string) string {
+		if strings.HasPrefix(link, "/") {
+			return baseURL + link
+		}
+		return link
+	}
+
+	title := article.Title
+	if article.OGTitle != "" {
+		title = article.OGTitle
+	}
+	description := article.Description
+	if description == "" {
+		description = excerpt(article.Content, excerptLength)
+	}
+	canonical := s.PublicURL(article)
+	if article.CanonicalURL != "" {
+		canonical = absolute(s.domains.BaseURL(article), article.CanonicalURL)
+	}
+	image := absolute(s.domains.HostURL(article), article.CoverImage)
+
+	twitterCard := article.TwitterCard
+	if twitterCard == "" {
+		twitterCard = TwitterCardSummary
+		if image != "" {
+			twitterCard = TwitterCardSummaryLarge
+		}
+	}
+
+	meta := theme.Meta{
+		Title:         title,
+		Description:   description,
+		CanonicalURL:  canonical,
+		Image:         image,
+		Author:        article.Author,
+		TwitterCard:   twitterCard,
+		PublishedTime: article.CreatedAt.Format(time.RFC3339),
+		ModifiedTime:  article.UpdatedAt.Format(time.RFC3339),
+	}
+	if site != nil {
+		meta.SiteName = site.Name
+	}
+
+	// schema.org Article 结构化数据
+	jsonLD := map[string]interface{}{
+		"@context":      "https://schema.org",
+		"@type":         "Article",
+		"headline":      article.Title,
+		"datePublished": meta.PublishedTime,
+		"dateModified":  meta.ModifiedTime,
+		"mainEntityOfPage": map[string]interface{}{
+			"@type": "WebPage",
+			"@id":   canonical,
+		},
+	}
+	if description != "" {
+		jsonLD["description"] = description
+	}
+	if image != "" {
+		jsonLD["image"] = []string{image}
+	}
+	if article.Author != "" {
+		jsonLD["author"] = map[string]interface{}{
+			"@type": "Person",
+			"name":  article.Author,
+		}
+	}
+	if meta.SiteName != "" {
+		jsonLD["publisher"] = map[string]interface{}{
+			"@type": "Organization",
+			"name":  meta.SiteName,
+		}
+	}
+	meta.JSONLD = jsonLD
+
+	return meta
+}
+
+// 从HTML内容截取纯文本摘要
+func excerpt(content string, length int) string {
+	text := html.UnescapeString(htmlTagPattern.ReplaceAllString(content, " "))
+	text = strings.Join(strings.Fields(text), " ")
+
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:length])) + "…"
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"static-hosting-server/internal/config"
+	"static-hosting-server/internal/events"
+	"static-hosting-server/internal/jobs"
+	"static-hosting-server/internal/theme"
+
+	"gorm.io/gorm"
+)
+
+// Services 共享依赖的一组服务，API、后台和定时任务使用同一组实例
+type Services struct {
+	Articles   *ArticleService
+	Domains    *DomainService
+	Sites      *SiteService
+	Previews   *PreviewService
+	Redirects  *RedirectService
+	Blueprints *BlueprintService
+	Notifier   *Notifier
+	Drafts     *DraftService
+	Jobs       *jobs.Queue
+}
+
+// 创建服务并相互注入依赖，bus为空时不发布事件。NewArticleService 等构造函数也通过这里创建，
+// 但每次调用都会生成一组新的依赖，只适合命令行工具等单独使用的场景
+func NewServices(db *gorm.DB, cfg *config.Config, themes *theme.Manager, bus *events.Bus) *Services {
+	notifier := NewNotifier(cfg)
+	domains := NewDomainService(db, cfg)
+	domains.notifier = notifier
+	queue := jobs.NewQueue(db, cfg)
+	sites := &SiteService{
+		db:      db,
+		cfg:     cfg,
+		domains: domains,
+		themes:  themes,
+	}
+	domains.sites = sites
+	redirects := &RedirectService{
+		db:    db,
+		cfg:   cfg,
+		sites: sites,
+	}
+	blueprints := &BlueprintService{
+		db:     db,
+		cfg:    cfg,
+		sites:  sites,
+		themes: themes,
+	}
+
+	articles := &ArticleService{
+		db:         db,
+		cfg:        cfg,
+		domains:    domains,
+		sites:      sites,
+		themes:     themes,
+		notifier:   notifier,
+		redirects:  redirects,
+		blueprints: blueprints,
+		events:     bus,
+		queue:      queue,
+	}
+	articles.registerJobs()
+
+	return &Services{
+		Articles: articles,
+		Domains:  domains,
+		Sites:    sites,
+		Previews: &PreviewService{
+			db:      db,
+			cfg:     cfg,
+			domains: domains,
+		},
+		Redirects:  redirects,
+		Blueprints: blueprints,
+		Notifier:   notifier,
+		Drafts:     &DraftService{db: db, cfg: cfg},
+		Jobs:       queue,
+	}
+}
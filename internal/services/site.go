@@ -0,0 +1,272 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"static-hosting-server/internal/config"
+	"static-hosting-server/internal/models"
+	"static-hosting-server/internal/theme"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 未绑定域名的站点通过默认域名下的 /s/<存储前缀> 访问
+const sitePathPrefix = "/s/"
+
+// 存储前缀只允许小写字母、数字和连字符
+var storagePrefixPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// 未配置存储前缀的站点使用的目录名，不能被其他站点手动指定
+var generatedPrefixPattern = regexp.MustCompile(`^site-[0-9]+$`)
+
+// 校验存储前缀：不能与默认站点的 articles 目录或自动生成的 site-<ID> 目录重叠，
+// 否则一个站点删除静态文件时会删掉另一个站点的文件
+func validateStoragePrefix(prefix string) error {
+	switch {
+	case !storagePrefixPattern.MatchString(prefix):
+		return fmt.Errorf("invalid storage prefix '%s'", prefix)
+	case prefix == "articles":
+		return fmt.Errorf("storage prefix '%s' is reserved for the default site", prefix)
+	case generatedPrefixPattern.MatchString(prefix):
+		return fmt.Errorf("storage prefix '%s' is reserved for sites without a storage prefix", prefix)
+	}
+	return nil
+}
+
+type SiteService struct {
+	db      *gorm.DB
+	cfg     *config.Config
+	domains *DomainService
+	themes  *theme.Manager
+}
+
+func NewSiteService(db *gorm.DB, cfg *config.Config) *SiteService {
+	return NewServices(db, cfg, theme.NewManager(cfg), nil).Sites
+}
+
+// 创建站点，host不为空时同时绑定站点域名
+func (s *SiteService) CreateSite(name, host, theme, storagePrefix string) (*models.Site, error) {
+	invalid := FieldErrors{}
+	if name == "" {
+		invalid["name"] = "site name is required"
+	}
+	if storagePrefix != "" {
+		if err := validateStoragePrefix(storagePrefix); err != nil {
+			invalid["storage_prefix"] = err.Error()
+		}
+	}
+	if theme != "" {
+		if err := s.themes.Validate(theme); err != nil {
+			invalid["theme"] = err.Error()
+		}
+	}
+	if err := invalid.err(); err != nil {
+		return nil, err
+	}
+
+	var existing models.Site
+	if err := s.db.Where("name = ?", name).First(&existing).Error; err == nil {
+		return nil, conflictf("site '%s' already exists", name)
+	}
+	if storagePrefix != "" {
+		// 已删除的站点可能还留有静态文件，同样不能重复使用其前缀
+		if err := s.db.Unscoped().Where("storage_prefix = ?", storagePrefix).First(&existing).Error; err == nil {
+			return nil, conflictf("storage prefix '%s' is already used by site '%s'", storagePrefix, existing.Name)
+		}
+	}
+
+	site := &models.Site{
+		Name:          name,
+		Theme:         theme,
+		StoragePrefix: storagePrefix,
+	}
+	if err := s.db.Create(site).Error; err != nil {
+		return nil, err
+	}
+
+	if host != "" {
+		if _, err := s.domains.CreateDomain(host, &site.ID); err != nil {
+			// 域名绑定失败时撤销站点创建
+			s.db.Unscoped().Delete(site)
+			var invalid FieldErrors
+			if errors.As(err, &invalid) {
+				// 请求中的域名字段为 domain
+				return nil, FieldErrors{"domain": invalid["host"]}
+			}
+			return nil, err
+		}
+	}
+
+	return site, nil
+}
+
+// 根据ID获取站点
+func (s *SiteService) GetSiteByID(id uint) (*models.Site, error) {
+	var site models.Site
+	if err := s.db.First(&site, id).Error; err != nil {
+		return nil, err
+	}
+	return &site, nil
+}
+
+// 获取站点列表
+func (s *SiteService) ListSites() ([]models.Site, error) {
+	var sites []models.Site
+	if err := s.db.Order("id ASC").Find(&sites).Error; err != nil {
+		return nil, err
+	}
+	return sites, nil
+}
+
+// 删除站点。站点下仍有文章（包括回收站中的）、管理员、文章模板或跳转时拒绝删除；
+// 站点的API密钥被停用，域名和自动保存的草稿一并删除
+func (s *SiteService) DeleteSite(id uint) error {
+	site, err := s.GetSiteByID(id)
+	if err != nil {
+		return err
+	}
+
+	remaining := []struct {
+		name  string
+		query *gorm.DB
+	}{
+		{"articles (including the trash)", s.db.Unscoped().Model(&models.Article{})},
+		{"users", s.db.Model(&models.User{})},
+		{"blueprints", s.db.Model(&models.Blueprint{})},
+		{"redirects", s.db.Model(&models.Redirect{})},
+	}
+	var inUse []string
+	for _, r := range remaining {
+		var count int64
+		if err := r.query.Where("site_id = ?", site.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			inUse = append(inUse, fmt.Sprintf("%d %s", count, r.name))
+		}
+	}
+	if len(inUse) > 0 {
+		return conflictf("site '%s' still has %s", site.Name, strings.Join(inUse, ", "))
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.APIKey{}).Where("site_id = ?", site.ID).Update("is_active", false).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("site_id = ?", site.ID).Delete(&models.ArticleDraft{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("site_id = ?", site.ID).Delete(&models.Domain{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(site).Error
+	})
+}
+
+// 检查请求中指定的站点是否存在，0 表示默认站点
+func (s *SiteService) CheckSiteID(id uint) error {
+	if id == 0 {
+		return nil
+	}
+	if _, err := s.GetSiteByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return FieldErrors{"site_id": fmt.Sprintf("site %d not found", id)}
+		}
+		return err
+	}
+	return nil
+}
+
+// 设置站点主题，主题校验通过后才会启用；传空字符串恢复使用全局主题
+func (s *SiteService) SetTheme(id uint, name string) (*models.Site, error) {
+	site, err := s.GetSiteByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != "" {
+		if err := s.themes.Validate(name); err != nil {
+			return nil, fieldError("theme", err)
+		}
+	}
+
+	if err := s.db.Model(site).Update("theme", name).Error; err != nil {
+		return nil, err
+	}
+	return site, nil
+}
+
+// 设置通过接口创建的文章的默认有效期（小时），0 表示使用全局配置
+func (s *SiteService) SetDefaultTTL(id uint, hours int) (*models.Site, error) {
+	if hours < 0 {
+		return nil, FieldErrors{"default_ttl_hours": "default_ttl_hours must not be negative"}
+	}
+	site, err := s.GetSiteByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(site).Update("default_ttl_hours", hours).Error; err != nil {
+		return nil, err
+	}
+	return site, nil
+}
+
+// 站点文章的默认有效期：站点未设置（或为默认站点）时使用 expiry.default_ttl，0 表示不过期
+func (s *SiteService) DefaultTTL(siteID uint) time.Duration {
+	if siteID != 0 {
+		if site, err := s.GetSiteByID(siteID); err == nil && site.DefaultTTLHours > 0 {
+			return time.Duration(site.DefaultTTLHours) * time.Hour
+		}
+	}
+	return s.cfg.Expiry.DefaultTTL
+}
+
+// 站点的静态文件目录前缀，默认站点为空，未配置时使用 site-<ID>
+func (s *SiteService) StoragePrefix(siteID uint) string {
+	if siteID == 0 {
+		return ""
+	}
+	if site, err := s.GetSiteByID(siteID); err == nil && site.StoragePrefix != "" {
+		return site.StoragePrefix
+	}
+	return fmt.Sprintf("site-%d", siteID)
+}
+
+// 站点在默认域名下的路径前缀，默认站点为空
+func (s *SiteService) SitePath(siteID uint) string {
+	if siteID == 0 {
+		return ""
+	}
+	return sitePathPrefix + s.StoragePrefix(siteID)
+}
+
+// 按 /s/<存储前缀> 中的前缀查找站点，未配置前缀的站点使用 site-<ID>
+func (s *SiteService) SiteByPath(prefix string) (uint, bool) {
+	var site models.Site
+	if generatedPrefixPattern.MatchString(prefix) {
+		if err := s.db.Where("id = ? AND storage_prefix = ?", strings.TrimPrefix(prefix, "site-"), "").First(&site).Error; err != nil {
+			return 0, false
+		}
+		return site.ID, true
+	}
+	if prefix == "" || s.db.Where("storage_prefix = ?", prefix).First(&site).Error != nil {
+		return 0, false
+	}
+	return site.ID, true
+}
+
+// 拆分 /s/<存储前缀>/... 形式的路径，返回前缀和站内路径
+func SplitSitePath(path string) (string, string, bool) {
+	if !strings.HasPrefix(path, sitePathPrefix) {
+		return "", "", false
+	}
+	prefix, rest, found := strings.Cut(strings.TrimPrefix(path, sitePathPrefix), "/")
+	if !found || prefix == "" {
+		return "", "", false
+	}
+	return prefix, "/" + rest, true
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"static-hosting-server/internal/models"
+)
+
+// 站点地图文件名，位于站点静态目录的根目录
+const sitemapFile = "sitemap.xml"
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// 重新生成默认站点和所有站点的 sitemap.xml，返回写入的文章总数
+func (s *ArticleService) RebuildSitemaps() (int, error) {
+	sites, err := s.sites.ListSites()
+	if err != nil {
+		return 0, err
+	}
+
+	siteIDs := []uint{0}
+	for _, site := range sites {
+		siteIDs = append(siteIDs, site.ID)
+	}
+
+	total := 0
+	for _, siteID := range siteIDs {
+		id := siteID
+		articles, err := s.ForSite(&id).ListIndexableArticles()
+		if err != nil {
+			return total, err
+		}
+		if err := s.writeSitemap(siteID, articles); err != nil {
+			return total, fmt.Errorf("failed to write sitemap for site %d: %w", siteID, err)
+		}
+		total += len(articles)
+	}
+	return total, nil
+}
+
+func (s *ArticleService) writeSitemap(siteID uint, articles []models.Article) error {
+	urlSet := sitemapURLSet{XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	for i := range articles {
+		urlSet.URLs = append(urlSet.URLs, sitemapURL{
+			Loc:     s.PublicURL(&articles[i]),
+			LastMod: articles[i].UpdatedAt.Format("2006-01-02"),
+		})
+	}
+
+	data, err := xml.MarshalIndent(urlSet, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := s.SitemapPath(siteID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	// 先写临时文件再替换，避免访问到写了一半的文件
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append([]byte(xml.Header), data...), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// 站点的 sitemap.xml 路径
+func (s *ArticleService) SitemapPath(siteID uint) string {
+	return filepath.Join(s.cfg.Storage.StaticPath, s.sites.StoragePrefix(siteID), sitemapFile)
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"static-hosting-server/internal/models"
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// 从标题生成slug的方式
+const (
+	SlugPinyin  = "pinyin"  // 汉字转为拼音，其他字符去掉变音符号后只保留英文字母和数字（默认）
+	SlugUnicode = "unicode" // 保留各语言的字母和数字，访问地址中按百分号编码
+	SlugASCII   = "ascii"   // 只保留英文字母和数字
+)
+
+// slug最大长度，与数据库字段一致
+const maxSlugLength = 255
+
+// 标题无法生成slug时使用的名称
+const fallbackSlug = "article"
+
+var (
+	asciiSlugPattern   = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	unicodeSlugPattern = regexp.MustCompile(`^[\p{Ll}\p{Lo}\p{Lm}\p{M}\p{N}]+(-[\p{Ll}\p{Lo}\p{Lm}\p{M}\p{N}]+)*$`)
+)
+
+var pinyinArgs = pinyin.NewArgs()
+
+// 当前配置的slug生成方式
+func (s *ArticleService) slugStrategy() string {
+	switch s.cfg.Slug.Strategy {
+	case SlugUnicode, SlugASCII:
+		return s.cfg.Slug.Strategy
+	}
+	return SlugPinyin
+}
+
+// 校验用户指定的slug：小写字母、数字和连字符，unicode 方式下还允许其他语言的字母
+func (s *ArticleService) validateSlug(slug string) error {
+	if len(slug) > maxSlugLength {
+		return fmt.Errorf("slug must not exceed %d bytes", maxSlugLength)
+	}
+
+	pattern := asciiSlugPattern
+	if s.slugStrategy() == SlugUnicode {
+		pattern = unicodeSlugPattern
+	}
+	if !pattern.MatchString(slug) {
+		return fmt.Errorf("invalid slug '%s': use lowercase letters, digits and single hyphens", slug)
+	}
+	return nil
+}
+
+// 从标题生成站点内唯一的slug，重名时依次追加 -2、-3…
+func (s *ArticleService) generateSlugFromTitle(siteID uint, title string) (string, error) {
+	base := Slugify(title, s.slugStrategy())
+	if base == "" {
+		base = fallbackSlug
+	}
+	return s.uniqueSlug(siteID, base)
+}
+
+// 在base后追加序号直到站点内没有重名，回收站中的文章不占用slug
+func (s *ArticleService) uniqueSlug(siteID uint, base string) (string, error) {
+	var taken []string
+	if err := s.db.Model(&models.Article{}).
+		Where("site_id = ? AND (slug = ? OR slug LIKE ?)", siteID, base, escapeLike(base)+"-%").
+		Pluck("slug", &taken).Error; err != nil {
+		return "", err
+	}
+
+	used := make(map[string]bool, len(taken))
+	for _, slug := range taken {
+		used[slug] = true
+	}
+	if !used[base] {
+		return base, nil
+	}
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d", base, n)
+		if !used[candidate] {
+			return candidate, nil
+		}
+	}
+}
+
+// 按指定方式把文本转换为slug
+func Slugify(text, strategy string) string {
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	if strategy != SlugUnicode {
+		text = removeDiacritics(text)
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			word.WriteRune(r)
+		case strategy == SlugPinyin && unicode.Is(unicode.Han, r):
+			// 每个汉字的拼音作为一个单词
+			flush()
+			if py := pinyin.SinglePinyin(r, pinyinArgs); len(py) > 0 {
+				words = append(words, py[0])
+			}
+		case strategy == SlugUnicode && (unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r)):
+			word.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	slug := strings.Join(words, "-")
+	return truncateSlug(slug)
+}
+
+// 去掉变音符号，如 café -> cafe
+func removeDiacritics(text string) string {
+	result, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), text)
+	if err != nil {
+		return text
+	}
+	return result
+}
+
+// 按单词截断到最大长度，并为重名序号预留位置
+func truncateSlug(slug string) string {
+	limit := maxSlugLength - 10
+	if len(slug) <= limit {
+		return slug
+	}
+	slug = slug[:limit]
+	if i := strings.LastIndex(slug, "-"); i > 0 {
+		return slug[:i]
+	}
+	// 没有单词边界时按字符截断，避免截断多字节字符
+	return strings.ToValidUTF8(slug, "")
+}
+
+// 转义LIKE中的通配符
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
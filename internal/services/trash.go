@@ -0,0 +1,168 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"static-hosting-server/internal/events"
+	"static-hosting-server/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TrashedArticle 回收站中的文章
+type TrashedArticle struct {
+	models.Article
+	DeletedAt time.Time  `json:"deleted_at"`
+	PurgeAt   *time.Time `json:"purge_at"` // 将被自动彻底删除的时间，未配置 trash.retention 时为空
+}
+
+// 当前站点范围内回收站中的文章查询
+func (s *ArticleService) trashed() *gorm.DB {
+	query := s.db.Unscoped().Model(&models.Article{}).Where("deleted_at IS NOT NULL")
+	if s.siteID != nil {
+		query = query.Where("site_id = ?", *s.siteID)
+	}
+	return query
+}
+
+func (s *ArticleService) toTrashed(article models.Article) TrashedArticle {
+	item := TrashedArticle{Article: article, DeletedAt: article.DeletedAt.Time}
+	if retention := s.cfg.Trash.Retention; retention > 0 {
+		purgeAt := item.DeletedAt.Add(retention)
+		item.PurgeAt = &purgeAt
+	}
+	return item
+}
+
+// 获取回收站中的文章列表，按删除时间倒序
+func (s *ArticleService) ListTrash(page, limit int) ([]TrashedArticle, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	limit = ArticleListOptions{Limit: limit}.limit()
+
+	var total int64
+	if err := s.trashed().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var articles []models.Article
+	if err := s.trashed().Order("deleted_at DESC, id DESC").Offset((page - 1) * limit).Limit(limit).
+		Find(&articles).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]TrashedArticle, 0, len(articles))
+	for _, article := range articles {
+		items = append(items, s.toTrashed(article))
+	}
+	return items, total, nil
+}
+
+// 根据ID获取回收站中的文章
+func (s *ArticleService) GetTrashedArticle(id string) (*TrashedArticle, error) {
+	var article models.Article
+	if err := s.trashed().Where("id = ?", id).First(&article).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, notFoundf("article %s is not in the trash", id)
+		}
+		return nil, err
+	}
+	item := s.toTrashed(article)
+	return &item, nil
+}
+
+// 从回收站恢复文章，slug不为空时改用新的slug（原slug已被其他文章使用时需要指定）。
+// 草稿以外的文章按状态重新输出静态文件
+func (s *ArticleService) RestoreArticle(id, slug string) (*models.Article, error) {
+	trashed, err := s.GetTrashedArticle(id)
+	if err != nil {
+		return nil, err
+	}
+	article := trashed.Article
+
+	if slug == "" {
+		slug = article.Slug
+	} else if slug != article.Slug {
+		if err := s.validateSlug(slug); err != nil {
+			return nil, fieldError("slug", err)
+		}
+	}
+	var existing models.Article
+	if err := s.db.Where("site_id = ? AND slug = ?", article.SiteID, slug).First(&existing).Error; err == nil {
+		return nil, conflictf("slug '%s' is used by article %s, restore with a new slug", slug, existing.ID)
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&article).Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"trash_key":  "",
+			"slug":       slug,
+			"version":    gorm.Expr("version + 1"),
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id = ?", id).First(&article).Error; err != nil {
+			return err
+		}
+		if article.Status != "draft" {
+			return s.enqueueRender(tx, &article)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 删除时设置的跳转不再需要
+	s.redirects.releasePath(s.db, article.SiteID, articlePathPrefix+article.Slug)
+
+	s.publish(events.ArticleRestored, &article)
+	return &article, nil
+}
+
+// 彻底删除回收站中的文章，无法恢复
+func (s *ArticleService) PurgeArticle(id string) error {
+	trashed, err := s.GetTrashedArticle(id)
+	if err != nil {
+		return err
+	}
+	return s.purge(&trashed.Article)
+}
+
+// 彻底删除在回收站中超过 trash.retention 的文章，返回删除的数量
+func (s *ArticleService) PurgeTrash() (int, error) {
+	retention := s.cfg.Trash.Retention
+	if retention <= 0 {
+		return 0, nil
+	}
+
+	var articles []models.Article
+	if err := s.trashed().Where("deleted_at < ?", time.Now().Add(-retention)).Find(&articles).Error; err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for i := range articles {
+		if err := s.purge(&articles[i]); err != nil {
+			fmt.Printf("Failed to purge article %s: %v\n", articles[i].ID, err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// 删除文章的数据及其预览链接和自动保存的草稿；静态文件已在移入回收站时删除
+func (s *ArticleService) purge(article *models.Article) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("article_id = ?", article.ID).Delete(&models.PreviewToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("article_id = ?", article.ID).Delete(&models.ArticleDraft{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(article).Error
+	})
+}
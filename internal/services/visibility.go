@@ -0,0 +1,106 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"static-hosting-server/internal/models"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 文章可见性
+const (
+	VisibilityPublic   = "public"   // 公开，可被索引
+	VisibilityUnlisted = "unlisted" // 凭链接访问，不出现在索引、订阅和站点地图中
+	VisibilityPassword = "password" // 需要输入密码才能访问
+)
+
+// 校验可见性取值
+func validVisibility(visibility string) bool {
+	switch visibility {
+	case VisibilityPublic, VisibilityUnlisted, VisibilityPassword:
+		return true
+	}
+	return false
+}
+
+// 文章是否可以出现在索引、订阅和站点地图中
+func IsIndexable(article *models.Article) bool {
+	return article.Visibility == "" || article.Visibility == VisibilityPublic
+}
+
+// 文章是否需要密码访问
+func IsPasswordProtected(article *models.Article) bool {
+	return article.Visibility == VisibilityPassword
+}
+
+// 校验访问密码
+func (s *ArticleService) CheckArticlePassword(article *models.Article, password string) bool {
+	if article.PasswordHash == "" {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(article.PasswordHash), []byte(password)) == nil
+}
+
+// 解锁后写入Cookie的访问令牌，修改密码后旧令牌自动失效
+func (s *ArticleService) AccessToken(article *models.Article) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.Security.JWTSecret))
+	mac.Write([]byte("unlock:" + article.ID + ":" + article.PasswordHash))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// 校验Cookie中的访问令牌
+func (s *ArticleService) CheckAccessToken(article *models.Article, token string) bool {
+	return token != "" && hmac.Equal([]byte(token), []byte(s.AccessToken(article)))
+}
+
+// 存放访问令牌的Cookie名称
+func AccessCookieName(article *models.Article) string {
+	return "article_access_" + article.ID
+}
+
+// 生成访问密码的哈希
+func hashArticlePassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// 跳转页面，用于不能直接输出内容的静态文件
+var redirectStubTemplate = template.Must(template.New("redirect").Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+    <meta charset="UTF-8">
+    <meta name="robots" content="noindex">
+    <meta http-equiv="refresh" content="0; url={{.}}">
+    <link rel="canonical" href="{{.}}">
+    <title>正在跳转…</title>
+</head>
+<body>
+    <p>页面已移至 <a href="{{.}}">{{.}}</a></p>
+    <script>window.location.replace({{.}});</script>
+</body>
+</html>
+`))
+
+// 在目录下写入跳转到target的index.html
+func writeRedirectStub(dir, target string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	file, err := os.Create(filepath.Join(dir, "index.html"))
+	if err != nil {
+		return fmt.Errorf("failed to create HTML file: %w", err)
+	}
+	defer file.Close()
+
+	return redirectStubTemplate.Execute(file, target)
+}
@@ -0,0 +1,16 @@
+package theme
+
+// Meta 文章页面的SEO、Open Graph、Twitter Card和结构化数据，模板中通过 .meta 访问，
+// 可直接使用内置的 {{template "seo" .}} 输出全部标签
+type Meta struct {
+	Title         string
+	Description   string
+	CanonicalURL  string
+	Image         string // 完整地址
+	Author        string
+	SiteName      string
+	TwitterCard   string // summary 或 summary_large_image
+	PublishedTime string // RFC 3339
+	ModifiedTime  string // RFC 3339
+	JSONLD        map[string]interface{}
+}
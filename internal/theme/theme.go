@@ -0,0 +1,263 @@
+package theme
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"static-hosting-server/internal/config"
+	"static-hosting-server/internal/models"
+	"static-hosting-server/templates"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 内置主题名称，模板嵌入在二进制文件中
+const DefaultTheme = "default"
+
+// 主题中用于渲染文章页面的模板
+const articleTemplate = "article.html"
+
+// 内置的SEO标签模板，所有主题都可以通过 {{template "seo" .}} 使用
+const seoTemplate = "seo.html"
+
+// 主题名称只允许字母、数字、下划线和连字符
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Manager 负责加载、校验和渲染主题模板
+type Manager struct {
+	cfg *config.Config
+
+	mu    sync.RWMutex
+	cache map[string]*template.Template
+}
+
+func NewManager(cfg *config.Config) *Manager {
+	return &Manager{
+		cfg:   cfg,
+		cache: make(map[string]*template.Template),
+	}
+}
+
+// 模板中可用的自定义函数
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"add":      func(a, b int) int { return a + b },
+		"safeHTML": func(s string) template.HTML { return template.HTML(s) },
+	}
+}
+
+// 调试模式下每次渲染都重新读取模板
+func (m *Manager) debug() bool {
+	return m.cfg.Server.Mode != "release"
+}
+
+// 主题目录
+func (m *Manager) themesPath() string {
+	if m.cfg.Theme.Path != "" {
+		return m.cfg.Theme.Path
+	}
+	return "./themes"
+}
+
+// 未指定主题时使用的主题
+func (m *Manager) DefaultName() string {
+	if m.cfg.Theme.Default != "" {
+		return m.cfg.Theme.Default
+	}
+	return DefaultTheme
+}
+
+// 按文章、站点、全局配置的顺序选择主题
+func (m *Manager) Resolve(article *models.Article, site *models.Site) string {
+	if article != nil && article.Theme != "" {
+		return article.Theme
+	}
+	if site != nil && site.Theme != "" {
+		return site.Theme
+	}
+	return m.DefaultName()
+}
+
+// 列出可用的主题
+func (m *Manager) List() ([]string, error) {
+	names := []string{DefaultTheme}
+
+	entries, err := os.ReadDir(m.themesPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return names, nil
+		}
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == DefaultTheme || !namePattern.MatchString(entry.Name()) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(m.themesPath(), entry.Name(), articleTemplate)); err == nil {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names[1:])
+
+	return names, nil
+}
+
+// 加载主题模板，非调试模式下缓存解析结果
+func (m *Manager) load(name string) (*template.Template, error) {
+	if !m.debug() {
+		m.mu.RLock()
+		tmpl, ok := m.cache[name]
+		m.mu.RUnlock()
+		if ok {
+			return tmpl, nil
+		}
+	}
+
+	tmpl, err := m.parse(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !m.debug() {
+		m.mu.Lock()
+		m.cache[name] = tmpl
+		m.mu.Unlock()
+	}
+
+	return tmpl, nil
+}
+
+// 解析主题目录下的所有模板
+func (m *Manager) parse(name string) (*template.Template, error) {
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid theme name '%s'", name)
+	}
+
+	var tmpl *template.Template
+	var err error
+	if name == DefaultTheme {
+		tmpl, err = parseDefault(m.debug())
+	} else {
+		dir := filepath.Join(m.themesPath(), name)
+		if _, statErr := os.Stat(dir); statErr != nil {
+			return nil, fmt.Errorf("theme '%s' not found", name)
+		}
+		tmpl, err = template.New(articleTemplate).Funcs(FuncMap()).ParseGlob(filepath.Join(dir, "*.html"))
+	}
+	if err == nil && tmpl.Lookup("seo") == nil {
+		// 主题未自定义时使用内置的SEO标签模板
+		_, err = tmpl.ParseFS(templates.FS, seoTemplate)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse theme '%s': %w", name, err)
+	}
+
+	if tmpl.Lookup(articleTemplate) == nil {
+		return nil, fmt.Errorf("theme '%s' has no %s", name, articleTemplate)
+	}
+	return tmpl, nil
+}
+
+// 解析内置主题；调试模式下优先读取磁盘上的 templates 目录以便热加载
+func parseDefault(debug bool) (*template.Template, error) {
+	tmpl := template.New(articleTemplate).Funcs(FuncMap())
+	diskPath := filepath.Join("templates", articleTemplate)
+	if debug {
+		if _, err := os.Stat(diskPath); err == nil {
+			return tmpl.ParseFiles(diskPath, filepath.Join("templates", seoTemplate))
+		}
+	}
+	return tmpl.ParseFS(templates.FS, articleTemplate, seoTemplate)
+}
+
+// 使用指定主题渲染文章页面
+func (m *Manager) Render(w io.Writer, name string, data interface{}) error {
+	tmpl, err := m.load(name)
+	if err != nil {
+		return err
+	}
+	return tmpl.ExecuteTemplate(w, articleTemplate, data)
+}
+
+// 校验主题：模板能够解析，并能使用示例文章完成渲染
+func (m *Manager) Validate(name string) error {
+	tmpl, err := m.parse(name)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	sample := map[string]interface{}{
+		"article": &models.Article{
+			ID:        "00000000-0000-0000-0000-000000000000",
+			Title:     "示例文章",
+			Content:   "<p>示例内容</p>",
+			Slug:      "example",
+			Status:    "published",
+			ExpiresAt: &now,
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		"domain": "http://example.com",
+		"meta": Meta{
+			Title:         "示例文章",
+			Description:   "示例内容",
+			CanonicalURL:  "http://example.com/p/example",
+			TwitterCard:   "summary",
+			PublishedTime: now.Format(time.RFC3339),
+			ModifiedTime:  now.Format(time.RFC3339),
+			JSONLD:        map[string]interface{}{"@context": "https://schema.org", "@type": "Article", "headline": "示例文章"},
+		},
+	}
+	if err := tmpl.ExecuteTemplate(io.Discard, articleTemplate, sample); err != nil {
+		return fmt.Errorf("theme '%s' failed to render: %w", name, err)
+	}
+
+	// 校验通过后刷新缓存
+	m.mu.Lock()
+	delete(m.cache, name)
+	m.mu.Unlock()
+
+	return nil
+}
+
+// 加载后台页面模板：调试模式下使用磁盘模板并热加载，否则使用嵌入的模板
+func (m *Manager) LoadAdminTemplates(router *gin.Engine) error {
+	router.SetFuncMap(FuncMap())
+
+	if m.debug() {
+		if _, err := os.Stat("templates"); err == nil {
+			router.LoadHTMLGlob("templates/*.html")
+			return nil
+		}
+	}
+
+	tmpl, err := template.New("").Funcs(FuncMap()).ParseFS(templates.FS, "*.html")
+	if err != nil {
+		return err
+	}
+	router.SetHTMLTemplate(tmpl)
+	return nil
+}
+
+// 提供主题 assets 目录下的静态资源
+func (m *Manager) AssetsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		if !namePattern.MatchString(name) || name == DefaultTheme {
+			c.Status(http.StatusNotFound)
+			return
+		}
+
+		assetsDir := filepath.Join(m.themesPath(), name, "assets")
+		c.File(filepath.Join(assetsDir, filepath.Clean("/"+c.Param("filepath"))))
+	}
+}
@@ -0,0 +1,159 @@
+package web
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"static-hosting-server/internal/auth"
+	"static-hosting-server/internal/models"
+	"static-hosting-server/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 按编辑器中的内容渲染预览，返回完整的HTML页面，由编辑器显示在iframe中
+func (h *WebHandler) PreviewArticleWeb(c *gin.Context) {
+	c.Header("X-Robots-Tag", "noindex, nofollow")
+	c.Header("Cache-Control", "no-store")
+
+	var buf bytes.Buffer
+	err := h.articles(c).PreviewArticle(&buf, c.PostForm("id"), services.ArticleInput{
+		Title:   c.PostForm("title"),
+		Content: c.PostForm("content"),
+		Slug:    c.PostForm("slug"),
+		Theme:   c.PostForm("theme"),
+		Meta:    articleMetaForm(c),
+	})
+	if err != nil {
+		c.String(http.StatusBadRequest, "预览失败：%s", err.Error())
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// 自动保存编辑中的标题和内容，并报告文章是否已被其他人修改
+func (h *WebHandler) AutosaveDraftWeb(c *gin.Context) {
+	articleID := c.PostForm("article_id")
+	baseVersion := parseVersion(c.PostForm("version"))
+
+	conflict := false
+	if articleID != "" {
+		article, err := h.articles(c).GetArticleByID(articleID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+			return
+		}
+		conflict = baseVersion != nil && article.Version != *baseVersion
+	}
+
+	userID, siteID := draftOwner(c)
+	input := services.DraftInput{
+		UserID:    userID,
+		SiteID:    siteID,
+		ArticleID: articleID,
+		Title:     c.PostForm("title"),
+		Content:   c.PostForm("content"),
+	}
+	if baseVersion != nil {
+		input.BaseVersion = *baseVersion
+	}
+	draft, err := h.draftService.SaveDraft(input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"saved_at": draft.UpdatedAt,
+		"conflict": conflict,
+	})
+}
+
+// 放弃自动保存的草稿
+func (h *WebHandler) DiscardDraftWeb(c *gin.Context) {
+	userID, siteID := draftOwner(c)
+	if err := h.draftService.DeleteDraft(userID, siteID, c.PostForm("article_id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// 上传编辑器中粘贴、拖入或选择的图片，返回访问地址
+func (h *WebHandler) UploadWeb(c *gin.Context) {
+	// 额外留出multipart表单其他部分的大小
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxUploadSize+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	url, err := h.articles(c).SaveUpload(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"url": url})
+}
+
+// 编辑器页面中可恢复的草稿，草稿不比文章新时不提示
+func (h *WebHandler) pendingDraft(c *gin.Context, article *models.Article) gin.H {
+	articleID := ""
+	if article != nil {
+		articleID = article.ID
+	}
+	userID, siteID := draftOwner(c)
+	draft, err := h.draftService.GetDraft(userID, siteID, articleID)
+	if err != nil {
+		return nil
+	}
+	if article != nil && !draft.UpdatedAt.After(article.UpdatedAt) {
+		return nil
+	}
+	return gin.H{
+		"title":      draft.Title,
+		"content":    draft.Content,
+		"updated_at": draft.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// 文章保存成功后删除草稿
+func (h *WebHandler) clearDraft(c *gin.Context, articleID string) {
+	userID, siteID := draftOwner(c)
+	h.draftService.DeleteDraft(userID, siteID, articleID)
+}
+
+// 草稿所属的管理员和站点
+func draftOwner(c *gin.Context) (uint, uint) {
+	var userID, siteID uint
+	if user := auth.AdminUser(c); user != nil {
+		userID = user.ID
+	}
+	if scope := auth.SiteScope(c); scope != nil {
+		siteID = *scope
+	}
+	return userID, siteID
+}
+
+// 解析表单中开始编辑时的文章版本，为空或格式错误时不检查冲突
+func parseVersion(value string) *int {
+	version, err := strconv.Atoi(value)
+	if err != nil || version <= 0 {
+		return nil
+	}
+	return &version
+}
@@ -1,12 +1,20 @@
 package web
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"static-hosting-server/internal/app"
 	"static-hosting-server/internal/auth"
 	"static-hosting-server/internal/config"
+	"static-hosting-server/internal/jobs"
 	"static-hosting-server/internal/models"
+	"static-hosting-server/internal/scheduler"
 	"static-hosting-server/internal/services"
+	"static-hosting-server/internal/theme"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,26 +22,39 @@ import (
 )
 
 type WebHandler struct {
-	db             *gorm.DB
-	cfg            *config.Config
-	authService    *auth.AuthService
-	articleService *services.ArticleService
+	db               *gorm.DB
+	cfg              *config.Config
+	authService      *auth.AuthService
+	articleService   *services.ArticleService
+	domainService    *services.DomainService
+	siteService      *services.SiteService
+	previewService   *services.PreviewService
+	redirectService  *services.RedirectService
+	blueprintService *services.BlueprintService
+	draftService     *services.DraftService
+	themeManager     *theme.Manager
+	jobQueue         *jobs.Queue
 }
 
-func NewWebHandler(db *gorm.DB, cfg *config.Config) *WebHandler {
-	authService := auth.NewAuthService(db, cfg)
-	articleService := services.NewArticleService(db, cfg)
-
+func NewWebHandler(app *app.App) *WebHandler {
 	return &WebHandler{
-		db:             db,
-		cfg:            cfg,
-		authService:    authService,
-		articleService: articleService,
+		db:               app.DB,
+		cfg:              app.Config,
+		authService:      app.Auth,
+		articleService:   app.Articles,
+		domainService:    app.Domains,
+		siteService:      app.Sites,
+		previewService:   app.Previews,
+		redirectService:  app.Redirects,
+		blueprintService: app.Blueprints,
+		draftService:     app.Drafts,
+		themeManager:     app.Themes,
+		jobQueue:         app.Jobs,
 	}
 }
 
-func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
-	handler := NewWebHandler(db, cfg)
+func SetupRoutes(router *gin.Engine, app *app.App) {
+	handler := NewWebHandler(app)
 
 	// 管理后台路由
 	admin := router.Group("/admin")
@@ -57,6 +78,42 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config) {
 			authenticated.GET("/articles/:id/edit", handler.EditArticlePage)
 			authenticated.POST("/articles/:id", handler.UpdateArticleWeb)
 			authenticated.POST("/articles/:id/delete", handler.DeleteArticleWeb)
+			authenticated.POST("/articles/bulk", handler.BulkArticlesWeb)
+			authenticated.POST("/articles/:id/revive", handler.ReviveArticleWeb)
+
+			// 回收站
+			authenticated.GET("/trash", handler.TrashList)
+			authenticated.POST("/trash/:id/restore", handler.RestoreArticleWeb)
+			authenticated.POST("/trash/:id/purge", handler.PurgeArticleWeb)
+
+			// 编辑器：预览、自动保存和图片上传
+			authenticated.POST("/articles/preview", handler.PreviewArticleWeb)
+			authenticated.POST("/drafts", handler.AutosaveDraftWeb)
+			authenticated.POST("/drafts/discard", handler.DiscardDraftWeb)
+			authenticated.POST("/uploads", handler.UploadWeb)
+
+			// 预览链接
+			authenticated.POST("/articles/:id/previews", handler.CreatePreviewWeb)
+			authenticated.POST("/previews/:id/revoke", handler.RevokePreviewWeb)
+
+			// 跳转管理
+			authenticated.GET("/redirects", handler.RedirectsList)
+			authenticated.POST("/redirects", handler.CreateRedirectWeb)
+			authenticated.POST("/redirects/:id/delete", handler.DeleteRedirectWeb)
+
+			// 文章模板
+			authenticated.GET("/blueprints", handler.BlueprintsList)
+			authenticated.GET("/blueprints/new", handler.NewBlueprintPage)
+			authenticated.POST("/blueprints", handler.CreateBlueprintWeb)
+			authenticated.GET("/blueprints/:id/edit", handler.EditBlueprintPage)
+			authenticated.POST("/blueprints/:id", handler.UpdateBlueprintWeb)
+			authenticated.POST("/blueprints/:id/delete", handler.DeleteBlueprintWeb)
+
+			// 后台任务
+			authenticated.GET("/jobs", handler.FailedJobsList)
+			authenticated.POST("/jobs/:id/retry", handler.RetryJobWeb)
+			authenticated.GET("/scheduler", handler.SchedulerRuns)
+			authenticated.POST("/scheduler/:name/run", handler.RunSchedulerJobWeb)
 		}
 	}
 }
@@ -74,9 +131,9 @@ func (h *WebHandler) Login(c *gin.Context) {
 	password := c.PostForm("password")
 
 	// 验证管理员凭据
-	if h.authService.ValidateAdminCredentials(username, password) {
+	if user, ok := h.authService.AuthenticateAdmin(username, password); ok {
 		// 生成会话token并设置cookie
-		sessionToken := h.authService.GenerateSessionToken()
+		sessionToken := h.authService.GenerateSessionToken(user)
 		c.SetCookie("admin_session", sessionToken, 3600*24*7, "/", "", false, true) // 7天有效期
 		c.Redirect(http.StatusFound, "/admin/dashboard")
 		return
@@ -91,7 +148,7 @@ func (h *WebHandler) Login(c *gin.Context) {
 // 仪表板
 func (h *WebHandler) Dashboard(c *gin.Context) {
 	// 获取统计信息
-	stats, err := h.getStatistics()
+	stats, err := h.getStatistics(auth.SiteScope(c))
 	if err != nil {
 		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
 			"error": err.Error(),
@@ -111,7 +168,7 @@ func (h *WebHandler) ArticlesList(c *gin.Context) {
 	limit := 20
 	status := c.Query("status")
 
-	articles, total, err := h.articleService.ListArticles(page, limit, status)
+	articles, total, err := h.articles(c).ListArticles(page, services.ArticleListOptions{Status: status, Limit: limit})
 	if err != nil {
 		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
 			"error": err.Error(),
@@ -126,16 +183,59 @@ func (h *WebHandler) ArticlesList(c *gin.Context) {
 		"page":     page,
 		"limit":    limit,
 		"status":   status,
+		"bulk":     bulkSummary(c),
 	})
 }
 
 // 新建文章页面
 func (h *WebHandler) NewArticlePage(c *gin.Context) {
-	c.HTML(http.StatusOK, "article_form.html", gin.H{
-		"title":  "新建文章",
-		"action": "/admin/articles",
-		"method": "POST",
-	})
+	domains, _ := h.domainService.ListDomains(auth.SiteScope(c))
+	blueprints, _ := h.blueprintService.ListBlueprints(auth.SiteScope(c))
+
+	data := gin.H{
+		"title":      "新建文章",
+		"action":     "/admin/articles",
+		"method":     "POST",
+		"domains":    domains,
+		"themes":     h.themeNames(),
+		"blueprints": blueprints,
+		// 已选择的域名
+		"selected_domain": "",
+		"draft":           h.pendingDraft(c, nil),
+	}
+
+	// 从文章模板开始：先填写占位符变量，再用生成的标题和内容预填表单
+	if blueprintID := c.Query("blueprint_id"); blueprintID != "" {
+		blueprint, ok := h.findBlueprint(c, blueprintID, false)
+		if !ok {
+			data["error"] = "Blueprint not found"
+		} else {
+			variables := make(map[string]string, len(blueprint.Placeholders))
+			fields := make([]gin.H, 0, len(blueprint.Placeholders))
+			for _, name := range blueprint.Placeholders {
+				variables[name] = c.Query("var_" + name)
+				fields = append(fields, gin.H{"name": name, "value": variables[name]})
+			}
+			data["blueprint"] = blueprint
+			data["blueprint_variables"] = fields
+
+			if c.Query("apply") != "" || len(blueprint.Placeholders) == 0 {
+				title, content, err := h.blueprintService.Render(blueprint, variables)
+				if err != nil {
+					data["error"] = err.Error()
+				} else {
+					data["blueprint_applied"] = true
+					data["form_data"] = gin.H{
+						"title":   title,
+						"content": content,
+						"theme":   blueprint.Theme,
+					}
+				}
+			}
+		}
+	}
+
+	c.HTML(http.StatusOK, "article_form.html", data)
 }
 
 // 创建文章（Web表单）
@@ -145,6 +245,11 @@ func (h *WebHandler) CreateArticleWeb(c *gin.Context) {
 	slug := c.PostForm("slug")
 	status := c.PostForm("status")
 	expiresAtStr := c.PostForm("expires_at")
+	domainIDStr := c.PostForm("domain_id")
+	themeName := c.PostForm("theme")
+	visibility := c.PostForm("visibility")
+	expiryAction := c.PostForm("expiry_action")
+	redirectURL := c.PostForm("redirect_url")
 
 	var expiresAt *time.Time
 	if expiresAtStr != "" {
@@ -153,24 +258,64 @@ func (h *WebHandler) CreateArticleWeb(c *gin.Context) {
 		}
 	}
 
-	_, err := h.articleService.CreateArticle(title, content, slug, status, expiresAt)
+	var domainID *uint
+	if parsed := parseDomainID(domainIDStr); parsed != nil && *parsed != 0 {
+		domainID = parsed
+	}
+
+	_, err := h.articles(c).CreateArticle(services.ArticleInput{
+		Title:     title,
+		Content:   content,
+		Slug:      slug,
+		Status:    status,
+		ExpiresAt: expiresAt,
+		DomainID:  domainID,
+		Theme:     themeName,
+
+		Visibility: visibility,
+		Password:   c.PostForm("password"),
+
+		ExpiryAction: expiryAction,
+		RedirectURL:  redirectURL,
+
+		Meta: articleMetaForm(c),
+	})
 	if err != nil {
+		domains, _ := h.domainService.ListDomains(auth.SiteScope(c))
 		c.HTML(http.StatusBadRequest, "article_form.html", gin.H{
-			"title":  "新建文章",
-			"action": "/admin/articles",
-			"method": "POST",
-			"error":  err.Error(),
+			"title":   "新建文章",
+			"action":  "/admin/articles",
+			"method":  "POST",
+			"error":   err.Error(),
+			"domains": domains,
+			"themes":  h.themeNames(),
+			// 已选择的域名
+			"selected_domain": domainIDStr,
 			"form_data": gin.H{
 				"title":      title,
 				"content":    content,
 				"slug":       slug,
 				"status":     status,
 				"expires_at": expiresAtStr,
+				"domain_id":  domainIDStr,
+				"theme":      themeName,
+				"visibility": visibility,
+
+				"expiry_action": expiryAction,
+				"redirect_url":  redirectURL,
+
+				"description":   c.PostForm("description"),
+				"canonical_url": c.PostForm("canonical_url"),
+				"cover_image":   c.PostForm("cover_image"),
+				"author":        c.PostForm("author"),
+				"og_title":      c.PostForm("og_title"),
+				"twitter_card":  c.PostForm("twitter_card"),
 			},
 		})
 		return
 	}
 
+	h.clearDraft(c, "")
 	c.Redirect(http.StatusFound, "/admin/articles")
 }
 
@@ -184,7 +329,7 @@ func (h *WebHandler) EditArticlePage(c *gin.Context) {
 		return
 	}
 
-	article, err := h.articleService.GetArticleByID(id)
+	article, err := h.articles(c).GetArticleByID(id)
 	if err != nil {
 		c.HTML(http.StatusNotFound, "error.html", gin.H{
 			"error": "Article not found",
@@ -192,11 +337,19 @@ func (h *WebHandler) EditArticlePage(c *gin.Context) {
 		return
 	}
 
+	domains, _ := h.domainService.ListDomains(auth.SiteScope(c))
+
 	c.HTML(http.StatusOK, "article_form.html", gin.H{
 		"title":   "编辑文章",
 		"action":  "/admin/articles/" + id,
 		"method":  "POST",
 		"article": article,
+		"domains": domains,
+		"themes":  h.themeNames(),
+		// 已选择的域名
+		"selected_domain": formatDomainID(article.DomainID),
+		"previews":        h.previewLinks(article),
+		"draft":           h.pendingDraft(c, article),
 	})
 }
 
@@ -222,19 +375,62 @@ func (h *WebHandler) UpdateArticleWeb(c *gin.Context) {
 		}
 	}
 
-	_, err := h.articleService.UpdateArticle(id, title, content, status, expiresAt)
+	// 表单中的空值表示使用默认域名
+	domainID := parseDomainID(c.PostForm("domain_id"))
+
+	_, err := h.articles(c).UpdateArticle(id, services.ArticleInput{
+		Title:     title,
+		Content:   content,
+		Slug:      c.PostForm("slug"),
+		Status:    status,
+		ExpiresAt: expiresAt,
+		DomainID:  domainID,
+		Theme:     c.PostForm("theme"),
+
+		// 密码留空表示不修改
+		Visibility: c.PostForm("visibility"),
+		Password:   c.PostForm("password"),
+
+		ExpiryAction: c.PostForm("expiry_action"),
+		RedirectURL:  c.PostForm("redirect_url"),
+
+		Meta: articleMetaForm(c),
+
+		ExpectedVersion: parseVersion(c.PostForm("version")),
+	})
 	if err != nil {
-		article, _ := h.articleService.GetArticleByID(id)
-		c.HTML(http.StatusBadRequest, "article_form.html", gin.H{
+		article, _ := h.articles(c).GetArticleByID(id)
+		domains, _ := h.domainService.ListDomains(auth.SiteScope(c))
+		data := gin.H{
 			"title":   "编辑文章",
 			"action":  "/admin/articles/" + id,
 			"method":  "POST",
-			"article": article,
+			"domains": domains,
+			"themes":  h.themeNames(),
 			"error":   err.Error(),
-		})
+			// 已选择的域名
+			"selected_domain": c.PostForm("domain_id"),
+		}
+		if article != nil {
+			if errors.Is(err, services.ErrEditConflict) {
+				// 表单中的版本改为最新版本，管理员选择合并或覆盖后再次提交
+				data["error"] = "文章在你编辑期间已被其他人修改，请选择如何处理"
+				data["conflict"] = gin.H{
+					"title":      article.Title,
+					"content":    article.Content,
+					"updated_at": article.UpdatedAt.Format("2006-01-02 15:04:05"),
+				}
+			}
+			// 保留提交的标题和内容，避免编辑的内容丢失
+			article.Title = title
+			article.Content = content
+			data["article"] = article
+		}
+		c.HTML(http.StatusBadRequest, "article_form.html", data)
 		return
 	}
 
+	h.clearDraft(c, id)
 	c.Redirect(http.StatusFound, "/admin/articles")
 }
 
@@ -246,7 +442,7 @@ func (h *WebHandler) DeleteArticleWeb(c *gin.Context) {
 		return
 	}
 
-	if err := h.articleService.DeleteArticle(id); err != nil {
+	if err := h.articles(c).DeleteArticle(id, nil); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -254,6 +450,577 @@ func (h *WebHandler) DeleteArticleWeb(c *gin.Context) {
 	c.Redirect(http.StatusFound, "/admin/articles")
 }
 
+// 恢复已过期的文章，过期时间按站点的默认有效期重新计算，未设置时不再过期
+func (h *WebHandler) ReviveArticleWeb(c *gin.Context) {
+	article, err := h.articles(c).GetArticleByID(c.Param("id"))
+	if err != nil {
+		c.HTML(http.StatusNotFound, "error.html", gin.H{
+			"error": "文章不存在",
+		})
+		return
+	}
+
+	var expiresAt *time.Time
+	if ttl := h.siteService.DefaultTTL(article.SiteID); ttl > 0 {
+		t := time.Now().Add(ttl)
+		expiresAt = &t
+	}
+	if _, err := h.articles(c).ReviveArticle(article.ID, expiresAt, nil); err != nil {
+		c.HTML(http.StatusBadRequest, "error.html", gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/admin/articles")
+}
+
+// 对文章列表中选中的文章执行批量操作，逐篇处理，完成后回到列表显示结果
+func (h *WebHandler) BulkArticlesWeb(c *gin.Context) {
+	extendDays, _ := strconv.Atoi(c.PostForm("extend_days"))
+	results, err := h.articles(c).BulkArticles(services.BulkInput{
+		Action:   c.PostForm("action"),
+		IDs:      c.PostFormArray("ids"),
+		ExtendBy: time.Duration(extendDays) * 24 * time.Hour,
+	})
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "error.html", gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	query := url.Values{
+		"bulk":      {c.PostForm("action")},
+		"succeeded": {strconv.Itoa(len(results) - failed)},
+		"failed":    {strconv.Itoa(failed)},
+	}
+	if status := c.PostForm("status"); status != "" {
+		query.Set("status", status)
+	}
+	c.Redirect(http.StatusFound, "/admin/articles?"+query.Encode())
+}
+
+// 批量操作完成后在文章列表显示的结果
+func bulkSummary(c *gin.Context) gin.H {
+	action := c.Query("bulk")
+	if action == "" {
+		return nil
+	}
+	succeeded, _ := strconv.Atoi(c.Query("succeeded"))
+	failed, _ := strconv.Atoi(c.Query("failed"))
+	return gin.H{
+		"action":    action,
+		"succeeded": succeeded,
+		"failed":    failed,
+	}
+}
+
+// 读取表单中的SEO字段，表单中的空值表示清除
+func articleMetaForm(c *gin.Context) services.ArticleMetaInput {
+	field := func(name string) *string {
+		if value, ok := c.GetPostForm(name); ok {
+			return &value
+		}
+		return nil
+	}
+	return services.ArticleMetaInput{
+		Description:  field("description"),
+		CanonicalURL: field("canonical_url"),
+		CoverImage:   field("cover_image"),
+		Author:       field("author"),
+		OGTitle:      field("og_title"),
+		TwitterCard:  field("twitter_card"),
+	}
+}
+
+// 解析表单中的域名ID，空值返回0表示默认域名
+func parseDomainID(value string) *uint {
+	var id uint
+	if value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil
+		}
+		id = uint(parsed)
+	}
+	return &id
+}
+
+// 创建预览链接（Web表单）
+func (h *WebHandler) CreatePreviewWeb(c *gin.Context) {
+	id := c.Param("id")
+	article, err := h.articles(c).GetArticleByID(id)
+	if err != nil {
+		c.HTML(http.StatusNotFound, "error.html", gin.H{
+			"error": "Article not found",
+		})
+		return
+	}
+
+	ttlHours, _ := strconv.Atoi(c.DefaultPostForm("ttl_hours", "24"))
+	if _, err := h.previewService.CreatePreview(article, time.Duration(ttlHours)*time.Hour); err != nil {
+		c.HTML(http.StatusBadRequest, "error.html", gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/admin/articles/"+id+"/edit")
+}
+
+// 撤销预览链接（Web表单）
+func (h *WebHandler) RevokePreviewWeb(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "error.html", gin.H{
+			"error": "Invalid preview ID",
+		})
+		return
+	}
+
+	preview, err := h.previewService.GetPreviewByID(uint(id))
+	if err == nil {
+		_, err = h.articles(c).GetArticleByID(preview.ArticleID)
+	}
+	if err != nil {
+		c.HTML(http.StatusNotFound, "error.html", gin.H{
+			"error": "Preview not found",
+		})
+		return
+	}
+
+	if err := h.previewService.RevokePreview(preview.ID); err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/admin/articles/"+preview.ArticleID+"/edit")
+}
+
+// 回收站页面
+func (h *WebHandler) TrashList(c *gin.Context) {
+	h.renderTrash(c, http.StatusOK, "")
+}
+
+// 从回收站恢复文章（Web表单），原slug已被占用时可以填写新的slug
+func (h *WebHandler) RestoreArticleWeb(c *gin.Context) {
+	if _, err := h.articles(c).RestoreArticle(c.Param("id"), strings.TrimSpace(c.PostForm("slug"))); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, services.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		h.renderTrash(c, status, err.Error())
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/admin/articles")
+}
+
+// 彻底删除回收站中的文章（Web表单）
+func (h *WebHandler) PurgeArticleWeb(c *gin.Context) {
+	if err := h.articles(c).PurgeArticle(c.Param("id")); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		h.renderTrash(c, status, err.Error())
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/admin/trash")
+}
+
+func (h *WebHandler) renderTrash(c *gin.Context, status int, errMsg string) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+
+	articles, total, err := h.articles(c).ListTrash(page, 20)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
+			"error": "Failed to load trash",
+		})
+		return
+	}
+
+	retention := ""
+	if r := h.cfg.Trash.Retention; r > 0 {
+		retention = r.String()
+		if r%(24*time.Hour) == 0 {
+			retention = fmt.Sprintf("%d 天", r/(24*time.Hour))
+		}
+	}
+
+	c.HTML(status, "trash.html", gin.H{
+		"title":     "回收站",
+		"articles":  articles,
+		"total":     total,
+		"page":      page,
+		"retention": retention,
+		"error":     errMsg,
+	})
+}
+
+// 跳转列表页面
+func (h *WebHandler) RedirectsList(c *gin.Context) {
+	redirects, err := h.redirectService.ListRedirects(auth.SiteScope(c))
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
+			"error": "Failed to load redirects",
+		})
+		return
+	}
+
+	c.HTML(http.StatusOK, "redirects.html", gin.H{
+		"title":     "跳转管理",
+		"redirects": redirects,
+	})
+}
+
+// 创建跳转（Web表单）
+func (h *WebHandler) CreateRedirectWeb(c *gin.Context) {
+	sourcePath := c.PostForm("source_path")
+	target := c.PostForm("target")
+	statusCode, _ := strconv.Atoi(c.PostForm("status_code"))
+
+	var siteID uint
+	if scope := auth.SiteScope(c); scope != nil {
+		siteID = *scope
+	}
+
+	if _, err := h.redirectService.CreateRedirect(siteID, sourcePath, target, statusCode); err != nil {
+		redirects, _ := h.redirectService.ListRedirects(auth.SiteScope(c))
+		c.HTML(http.StatusBadRequest, "redirects.html", gin.H{
+			"title":     "跳转管理",
+			"redirects": redirects,
+			"error":     err.Error(),
+			"form_data": gin.H{
+				"source_path": sourcePath,
+				"target":      target,
+			},
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/admin/redirects")
+}
+
+// 删除跳转（Web表单）
+func (h *WebHandler) DeleteRedirectWeb(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "error.html", gin.H{
+			"error": "Invalid redirect ID",
+		})
+		return
+	}
+
+	redirect, err := h.redirectService.GetRedirectByID(uint(id))
+	scope := auth.SiteScope(c)
+	if err != nil || (scope != nil && redirect.SiteID != *scope) {
+		c.HTML(http.StatusNotFound, "error.html", gin.H{
+			"error": "Redirect not found",
+		})
+		return
+	}
+
+	if err := h.redirectService.DeleteRedirect(redirect.ID); err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/admin/redirects")
+}
+
+// 失败任务列表页面
+func (h *WebHandler) FailedJobsList(c *gin.Context) {
+	failed, err := h.jobQueue.ListFailed(auth.SiteScope(c))
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
+			"error": "Failed to load jobs",
+		})
+		return
+	}
+
+	c.HTML(http.StatusOK, "jobs.html", gin.H{
+		"title": "后台任务",
+		"jobs":  failed,
+	})
+}
+
+// 立即重试失败的任务（Web表单）
+func (h *WebHandler) RetryJobWeb(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "error.html", gin.H{
+			"error": "Invalid job ID",
+		})
+		return
+	}
+
+	job, err := h.jobQueue.GetJob(uint(id))
+	scope := auth.SiteScope(c)
+	if err != nil || (scope != nil && job.SiteID != *scope) {
+		c.HTML(http.StatusNotFound, "error.html", gin.H{
+			"error": "Job not found",
+		})
+		return
+	}
+
+	if err := h.jobQueue.Retry(job.ID); err != nil {
+		c.HTML(http.StatusBadRequest, "error.html", gin.H{
+			"error": "Job is not failed",
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/admin/jobs")
+}
+
+// 定时任务执行记录页面，定时任务处理所有站点，只对不绑定站点的管理员开放
+func (h *WebHandler) SchedulerRuns(c *gin.Context) {
+	if auth.SiteScope(c) != nil {
+		c.HTML(http.StatusForbidden, "error.html", gin.H{
+			"error": "Scheduler is only available to global administrators",
+		})
+		return
+	}
+
+	runs, err := scheduler.ListRuns(h.db, 100)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
+			"error": "Failed to load scheduler runs",
+		})
+		return
+	}
+
+	c.HTML(http.StatusOK, "scheduler.html", gin.H{
+		"title":  "定时任务",
+		"leader": scheduler.CurrentLeader(h.db),
+		"jobs":   scheduler.Definitions(h.cfg),
+		"runs":   runs,
+		"queued": c.Query("queued"),
+	})
+}
+
+// 立即执行定时任务（Web表单）
+func (h *WebHandler) RunSchedulerJobWeb(c *gin.Context) {
+	if auth.SiteScope(c) != nil {
+		c.HTML(http.StatusForbidden, "error.html", gin.H{
+			"error": "Scheduler is only available to global administrators",
+		})
+		return
+	}
+
+	name := c.Param("name")
+	if err := scheduler.Trigger(h.cfg, h.jobQueue, name); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, scheduler.ErrUnknownJob) {
+			status = http.StatusNotFound
+		}
+		c.HTML(status, "error.html", gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/admin/scheduler?queued="+url.QueryEscape(name))
+}
+
+// 文章模板列表页面
+func (h *WebHandler) BlueprintsList(c *gin.Context) {
+	blueprints, err := h.blueprintService.ListBlueprints(auth.SiteScope(c))
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
+			"error": "Failed to load blueprints",
+		})
+		return
+	}
+
+	c.HTML(http.StatusOK, "blueprints.html", gin.H{
+		"title":      "文章模板",
+		"blueprints": blueprints,
+	})
+}
+
+// 新建文章模板页面
+func (h *WebHandler) NewBlueprintPage(c *gin.Context) {
+	c.HTML(http.StatusOK, "blueprint_form.html", gin.H{
+		"title":  "新建文章模板",
+		"action": "/admin/blueprints",
+		"themes": h.themeNames(),
+	})
+}
+
+// 创建文章模板（Web表单）
+func (h *WebHandler) CreateBlueprintWeb(c *gin.Context) {
+	input := blueprintFormInput(c)
+
+	var siteID uint
+	if scope := auth.SiteScope(c); scope != nil {
+		siteID = *scope
+	}
+
+	if _, err := h.blueprintService.CreateBlueprint(siteID, input); err != nil {
+		c.HTML(http.StatusBadRequest, "blueprint_form.html", gin.H{
+			"title":  "新建文章模板",
+			"action": "/admin/blueprints",
+			"themes": h.themeNames(),
+			"error":  err.Error(),
+			"form_data": gin.H{
+				"name":        input.Name,
+				"description": input.Description,
+				"title":       input.Title,
+				"content":     input.Content,
+				"theme":       input.Theme,
+			},
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/admin/blueprints")
+}
+
+// 编辑文章模板页面
+func (h *WebHandler) EditBlueprintPage(c *gin.Context) {
+	blueprint, ok := h.findBlueprint(c, c.Param("id"), true)
+	if !ok {
+		c.HTML(http.StatusNotFound, "error.html", gin.H{
+			"error": "Blueprint not found",
+		})
+		return
+	}
+
+	c.HTML(http.StatusOK, "blueprint_form.html", gin.H{
+		"title":     "编辑文章模板",
+		"action":    "/admin/blueprints/" + c.Param("id"),
+		"themes":    h.themeNames(),
+		"blueprint": blueprint,
+	})
+}
+
+// 更新文章模板（Web表单）
+func (h *WebHandler) UpdateBlueprintWeb(c *gin.Context) {
+	blueprint, ok := h.findBlueprint(c, c.Param("id"), true)
+	if !ok {
+		c.HTML(http.StatusNotFound, "error.html", gin.H{
+			"error": "Blueprint not found",
+		})
+		return
+	}
+
+	if _, err := h.blueprintService.UpdateBlueprint(blueprint.ID, blueprintFormInput(c)); err != nil {
+		c.HTML(http.StatusBadRequest, "blueprint_form.html", gin.H{
+			"title":     "编辑文章模板",
+			"action":    "/admin/blueprints/" + c.Param("id"),
+			"themes":    h.themeNames(),
+			"blueprint": blueprint,
+			"error":     err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/admin/blueprints")
+}
+
+// 删除文章模板（Web表单）
+func (h *WebHandler) DeleteBlueprintWeb(c *gin.Context) {
+	blueprint, ok := h.findBlueprint(c, c.Param("id"), true)
+	if !ok {
+		c.HTML(http.StatusNotFound, "error.html", gin.H{
+			"error": "Blueprint not found",
+		})
+		return
+	}
+
+	if err := h.blueprintService.DeleteBlueprint(blueprint.ID); err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/admin/blueprints")
+}
+
+// 读取文章模板表单
+func blueprintFormInput(c *gin.Context) services.BlueprintInput {
+	return services.BlueprintInput{
+		Name:        c.PostForm("name"),
+		Description: c.PostForm("description"),
+		Title:       c.PostForm("title"),
+		Content:     c.PostForm("content"),
+		Theme:       c.PostForm("theme"),
+	}
+}
+
+// 查找当前管理员可访问的文章模板，默认站点的模板所有站点可用，修改时只能操作本站点的模板
+func (h *WebHandler) findBlueprint(c *gin.Context, idStr string, write bool) (*models.Blueprint, bool) {
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	blueprint, err := h.blueprintService.GetBlueprintByID(uint(id))
+	if err != nil {
+		return nil, false
+	}
+	if scope := auth.SiteScope(c); scope != nil {
+		if (write && blueprint.SiteID != *scope) || !h.blueprintService.Usable(blueprint, *scope) {
+			return nil, false
+		}
+	}
+	return blueprint, true
+}
+
+// 文章的预览链接及其访问地址
+func (h *WebHandler) previewLinks(article *models.Article) []gin.H {
+	previews, err := h.previewService.ListPreviews(article.ID)
+	if err != nil {
+		return nil
+	}
+
+	now := time.Now()
+	links := make([]gin.H, 0, len(previews))
+	for i := range previews {
+		preview := &previews[i]
+		links = append(links, gin.H{
+			"preview": preview,
+			"url":     h.previewService.URL(preview, article),
+			"active":  preview.RevokedAt == nil && preview.ExpiresAt.After(now),
+		})
+	}
+	return links
+}
+
+// 可选的主题列表，读取失败时只返回内置主题
+func (h *WebHandler) themeNames() []string {
+	names, err := h.themeManager.List()
+	if err != nil {
+		return []string{theme.DefaultTheme}
+	}
+	return names
+}
+
+// 格式化域名ID，用于表单回显
+func formatDomainID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
 // 退出登录
 func (h *WebHandler) Logout(c *gin.Context) {
 	// 清除会话cookie
@@ -261,22 +1028,35 @@ func (h *WebHandler) Logout(c *gin.Context) {
 	c.Redirect(http.StatusFound, "/admin/login")
 }
 
+// 当前管理员可操作的文章服务，限定在管理员所属站点内
+func (h *WebHandler) articles(c *gin.Context) *services.ArticleService {
+	return h.articleService.ForSite(auth.SiteScope(c))
+}
+
 // 获取统计信息
-func (h *WebHandler) getStatistics() (map[string]interface{}, error) {
+func (h *WebHandler) getStatistics(siteID *uint) (map[string]interface{}, error) {
 	var totalArticles int64
 	var publishedArticles int64
 	var draftArticles int64
 
-	// 修正：使用正确的模型引用
-	if err := h.db.Model(&models.Article{}).Count(&totalArticles).Error; err != nil {
+	// 按管理员所属站点统计
+	articles := func() *gorm.DB {
+		query := h.db.Model(&models.Article{})
+		if siteID != nil {
+			query = query.Where("site_id = ?", *siteID)
+		}
+		return query
+	}
+
+	if err := articles().Count(&totalArticles).Error; err != nil {
 		return nil, err
 	}
 
-	if err := h.db.Model(&models.Article{}).Where("status = ?", "published").Count(&publishedArticles).Error; err != nil {
+	if err := articles().Where("status = ?", "published").Count(&publishedArticles).Error; err != nil {
 		return nil, err
 	}
 
-	if err := h.db.Model(&models.Article{}).Where("status = ?", "draft").Count(&draftArticles).Error; err != nil {
+	if err := articles().Where("status = ?", "draft").Count(&draftArticles).Error; err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,248 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// CreateArticle 创建文章，返回文章和公开访问地址（未发布时为空）
+func (c *Client) CreateArticle(ctx context.Context, input ArticleCreate) (*Article, string, error) {
+	var article Article
+	resp, err := c.do(ctx, http.MethodPost, "/articles", nil, input, &article)
+	if err != nil {
+		return nil, "", err
+	}
+	return &article, resp.URL, nil
+}
+
+// GetArticle 获取文章
+func (c *Client) GetArticle(ctx context.Context, id string) (*Article, error) {
+	var article Article
+	if _, err := c.do(ctx, http.MethodGet, "/articles/"+url.PathEscape(id), nil, nil, &article); err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
+// UpdateArticle 更新文章，返回文章和公开访问地址（未发布时为空）
+func (c *Client) UpdateArticle(ctx context.Context, id string, input ArticleUpdate) (*Article, string, error) {
+	var article Article
+	resp, err := c.do(ctx, http.MethodPut, "/articles/"+url.PathEscape(id), nil, input, &article)
+	if err != nil {
+		return nil, "", err
+	}
+	return &article, resp.URL, nil
+}
+
+// UpdateArticleIfMatch 只在文章的当前版本为version时更新，否则返回的错误满足 IsPreconditionFailed
+func (c *Client) UpdateArticleIfMatch(ctx context.Context, id string, version int, input ArticleUpdate) (*Article, string, error) {
+	var article Article
+	resp, err := c.doWithHeader(ctx, http.MethodPut, "/articles/"+url.PathEscape(id), nil, ifMatch(version), input, &article)
+	if err != nil {
+		return nil, "", err
+	}
+	return &article, resp.URL, nil
+}
+
+// PatchArticle 部分更新文章（JSON Merge Patch）：只修改patch中的字段，值为nil时清除该字段
+func (c *Client) PatchArticle(ctx context.Context, id string, patch ArticlePatch) (*Article, string, error) {
+	return c.patchArticle(ctx, id, patch, nil)
+}
+
+// PatchArticleIfMatch 只在文章的当前版本为version时部分更新，否则返回的错误满足 IsPreconditionFailed
+func (c *Client) PatchArticleIfMatch(ctx context.Context, id string, version int, patch ArticlePatch) (*Article, string, error) {
+	return c.patchArticle(ctx, id, patch, ifMatch(version))
+}
+
+func (c *Client) patchArticle(ctx context.Context, id string, patch ArticlePatch, header http.Header) (*Article, string, error) {
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Content-Type", "application/merge-patch+json")
+
+	var article Article
+	resp, err := c.doWithHeader(ctx, http.MethodPatch, "/articles/"+url.PathEscape(id), nil, header, patch, &article)
+	if err != nil {
+		return nil, "", err
+	}
+	return &article, resp.URL, nil
+}
+
+// DeleteArticle 将文章移入回收站，redirectTo不为空时文章原地址跳转到该地址
+func (c *Client) DeleteArticle(ctx context.Context, id, redirectTo string) error {
+	return c.deleteArticle(ctx, id, redirectTo, nil)
+}
+
+// DeleteArticleIfMatch 只在文章的当前版本为version时删除，否则返回的错误满足 IsPreconditionFailed
+func (c *Client) DeleteArticleIfMatch(ctx context.Context, id string, version int, redirectTo string) error {
+	return c.deleteArticle(ctx, id, redirectTo, ifMatch(version))
+}
+
+func (c *Client) deleteArticle(ctx context.Context, id, redirectTo string, header http.Header) error {
+	var query url.Values
+	if redirectTo != "" {
+		query = url.Values{"redirect_to": {redirectTo}}
+	}
+	_, err := c.doWithHeader(ctx, http.MethodDelete, "/articles/"+url.PathEscape(id), query, header, nil, nil)
+	return err
+}
+
+// BulkArticles 批量操作文章。原子操作失败时除错误外也返回每篇文章的结果
+func (c *Client) BulkArticles(ctx context.Context, req BulkRequest) (*BulkReport, error) {
+	var report BulkReport
+	resp, err := c.do(ctx, http.MethodPost, "/articles/bulk", nil, req, &report)
+	if err != nil {
+		if resp != nil && len(resp.Data) > 0 && json.Unmarshal(resp.Data, &report) == nil {
+			return &report, err
+		}
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ExtendArticleExpiry 将文章的过期时间延长hours小时，已被过期处理的文章返回的错误满足 IsConflict，需使用 ReviveArticle
+func (c *Client) ExtendArticleExpiry(ctx context.Context, id string, hours int) (*Article, error) {
+	var article Article
+	body := map[string]int{"extend_hours": hours}
+	if _, err := c.do(ctx, http.MethodPost, "/articles/"+url.PathEscape(id)+"/extend", nil, body, &article); err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
+// MakeArticlePermanent 取消文章的过期时间
+func (c *Client) MakeArticlePermanent(ctx context.Context, id string) (*Article, error) {
+	var article Article
+	if _, err := c.do(ctx, http.MethodPost, "/articles/"+url.PathEscape(id)+"/make-permanent", nil, nil, &article); err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
+// ReviveArticle 重新发布已过期的文章，返回文章和公开访问地址
+func (c *Client) ReviveArticle(ctx context.Context, id string, input ArticleRevive) (*Article, string, error) {
+	var article Article
+	resp, err := c.do(ctx, http.MethodPost, "/articles/"+url.PathEscape(id)+"/revive", nil, input, &article)
+	if err != nil {
+		return nil, "", err
+	}
+	return &article, resp.URL, nil
+}
+
+// ListTrash 按页码获取回收站中的文章，page和limit为0时使用默认值
+func (c *Client) ListTrash(ctx context.Context, page, limit int) (*TrashPage, error) {
+	query := url.Values{}
+	if page > 0 {
+		query.Set("page", strconv.Itoa(page))
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	var trash TrashPage
+	if _, err := c.do(ctx, http.MethodGet, "/trash", query, nil, &trash); err != nil {
+		return nil, err
+	}
+	return &trash, nil
+}
+
+// RestoreArticle 从回收站恢复文章，返回文章和公开访问地址。slug为空时使用原slug，
+// 原slug已被其他文章使用时返回的错误满足 IsConflict
+func (c *Client) RestoreArticle(ctx context.Context, id, slug string) (*Article, string, error) {
+	var body interface{}
+	if slug != "" {
+		body = map[string]string{"slug": slug}
+	}
+
+	var article Article
+	resp, err := c.do(ctx, http.MethodPost, "/trash/"+url.PathEscape(id)+"/restore", nil, body, &article)
+	if err != nil {
+		return nil, "", err
+	}
+	return &article, resp.URL, nil
+}
+
+// PurgeArticle 彻底删除回收站中的文章
+func (c *Client) PurgeArticle(ctx context.Context, id string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/trash/"+url.PathEscape(id), nil, nil, nil)
+	return err
+}
+
+// 按文章版本生成 If-Match 请求头，与服务器返回的 ETag 格式相同
+func ifMatch(version int) http.Header {
+	return http.Header{"If-Match": {`"` + strconv.Itoa(version) + `"`}}
+}
+
+// ListArticles 按页码获取文章列表
+func (c *Client) ListArticles(ctx context.Context, opts ListOptions) (*ArticlePage, error) {
+	query := opts.query()
+	if opts.Page > 0 {
+		query.Set("page", strconv.Itoa(opts.Page))
+	}
+
+	var page ArticlePage
+	if _, err := c.do(ctx, http.MethodGet, "/articles", query, nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// ListArticlesAfter 按游标获取文章列表，cursor为空时从最新的文章开始，忽略 opts.Page
+func (c *Client) ListArticlesAfter(ctx context.Context, cursor string, opts ListOptions) (*ArticleCursorPage, error) {
+	query := opts.query()
+	query.Set("cursor", cursor)
+
+	var page ArticleCursorPage
+	if _, err := c.do(ctx, http.MethodGet, "/articles", query, nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+func (o ListOptions) query() url.Values {
+	query := url.Values{}
+	if o.Limit > 0 {
+		query.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Status != "" {
+		query.Set("status", o.Status)
+	}
+	if len(o.Fields) > 0 {
+		query.Set("fields", strings.Join(o.Fields, ","))
+	}
+	return query
+}
+
+// CreatePreview 创建草稿预览链接，ttlHours为0时使用默认有效期，返回预览链接和访问地址
+func (c *Client) CreatePreview(ctx context.Context, articleID string, ttlHours int) (*Preview, string, error) {
+	body := map[string]int{}
+	if ttlHours > 0 {
+		body["ttl_hours"] = ttlHours
+	}
+
+	var preview Preview
+	resp, err := c.do(ctx, http.MethodPost, "/articles/"+url.PathEscape(articleID)+"/previews", nil, body, &preview)
+	if err != nil {
+		return nil, "", err
+	}
+	return &preview, resp.URL, nil
+}
+
+// ListPreviews 获取文章的预览链接
+func (c *Client) ListPreviews(ctx context.Context, articleID string) ([]PreviewLink, error) {
+	var previews []PreviewLink
+	if _, err := c.do(ctx, http.MethodGet, "/articles/"+url.PathEscape(articleID)+"/previews", nil, nil, &previews); err != nil {
+		return nil, err
+	}
+	return previews, nil
+}
+
+// RevokePreview 撤销预览链接
+func (c *Client) RevokePreview(ctx context.Context, id uint) error {
+	_, err := c.do(ctx, http.MethodDelete, "/previews/"+strconv.FormatUint(uint64(id), 10), nil, nil, nil)
+	return err
+}
@@ -0,0 +1,134 @@
+// Package client 是 Static Hosting Server 接口的 Go 客户端，与 /api/openapi.json 中的文档保持一致。
+//
+//	c := client.New("http://localhost:8080", "demo-api-key-12345")
+//	article, url, err := c.CreateArticle(ctx, client.ArticleCreate{Title: "Hello", Content: "<p>Hi</p>", Status: "published"})
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client 使用API密钥访问 /api 接口
+type Client struct {
+	BaseURL    string // 服务器地址，如 http://localhost:8080
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+// New 创建客户端
+func New(baseURL, apiKey string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		APIKey:     apiKey,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Response 接口统一的响应格式（N8nResponse）
+type Response struct {
+	Success bool              `json:"success"`
+	Data    json.RawMessage   `json:"data,omitempty"`
+	Code    string            `json:"code,omitempty"`
+	Error   string            `json:"error,omitempty"`
+	Errors  map[string]string `json:"errors,omitempty"`
+	URL     string            `json:"url,omitempty"`
+}
+
+// Error 接口返回的错误
+type Error struct {
+	StatusCode int
+	Code       string // 错误码，如 not_found、conflict、validation_failed
+	Message    string
+	Fields     map[string]string // 校验失败（422）时各字段的错误
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("api error %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound 是否为资源不存在导致的错误
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsConflict 是否为与已有数据冲突（如slug重复）导致的错误
+func IsConflict(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict
+}
+
+// IsPreconditionFailed 是否为文章已被修改（If-Match 不匹配）导致的错误
+func IsPreconditionFailed(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusPreconditionFailed
+}
+
+// 发送请求并解析响应，out不为空时把 data 解析到 out
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) (*Response, error) {
+	return c.doWithHeader(ctx, method, path, query, nil, body, out)
+}
+
+// 发送带额外请求头的请求
+func (c *Client) doWithHeader(ctx context.Context, method, path string, query url.Values, header http.Header, body, out interface{}) (*Response, error) {
+	endpoint := c.BaseURL + "/api" + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-API-Key", c.APIKey)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result Response
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, &Error{StatusCode: resp.StatusCode, Message: fmt.Sprintf("invalid response: %v", err)}
+	}
+	if resp.StatusCode >= 400 || !result.Success {
+		return &result, &Error{StatusCode: resp.StatusCode, Code: result.Code, Message: result.Error, Fields: result.Errors}
+	}
+
+	if out != nil && len(result.Data) > 0 {
+		if err := json.Unmarshal(result.Data, out); err != nil {
+			return &result, err
+		}
+	}
+	return &result, nil
+}
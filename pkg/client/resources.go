@@ -0,0 +1,220 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+func idPath(prefix string, id uint) string {
+	return prefix + "/" + strconv.FormatUint(uint64(id), 10)
+}
+
+// CreateAPIKey 创建API密钥
+func (c *Client) CreateAPIKey(ctx context.Context, input APIKeyCreate) (*APIKey, error) {
+	var key APIKey
+	if _, err := c.do(ctx, http.MethodPost, "/keys", nil, input, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListAPIKeys 获取API密钥列表，返回的密钥不包含 Key
+func (c *Client) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	var keys []APIKey
+	if _, err := c.do(ctx, http.MethodGet, "/keys", nil, nil, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// DeleteAPIKey 停用API密钥，停用后的密钥立即无法通过认证
+func (c *Client) DeleteAPIKey(ctx context.Context, id uint) error {
+	_, err := c.do(ctx, http.MethodDelete, idPath("/keys", id), nil, nil, nil)
+	return err
+}
+
+// CreateDomain 添加自定义域名，siteID为空时不绑定站点
+func (c *Client) CreateDomain(ctx context.Context, host string, siteID *uint) (*Domain, error) {
+	body := map[string]interface{}{"host": host}
+	if siteID != nil {
+		body["site_id"] = *siteID
+	}
+
+	var domain Domain
+	if _, err := c.do(ctx, http.MethodPost, "/domains", nil, body, &domain); err != nil {
+		return nil, err
+	}
+	return &domain, nil
+}
+
+// ListDomains 获取自定义域名列表
+func (c *Client) ListDomains(ctx context.Context) ([]Domain, error) {
+	var domains []Domain
+	if _, err := c.do(ctx, http.MethodGet, "/domains", nil, nil, &domains); err != nil {
+		return nil, err
+	}
+	return domains, nil
+}
+
+// DeleteDomain 删除自定义域名
+func (c *Client) DeleteDomain(ctx context.Context, id uint) error {
+	_, err := c.do(ctx, http.MethodDelete, idPath("/domains", id), nil, nil, nil)
+	return err
+}
+
+// CreateSite 创建站点
+func (c *Client) CreateSite(ctx context.Context, input SiteCreate) (*Site, error) {
+	var site Site
+	if _, err := c.do(ctx, http.MethodPost, "/sites", nil, input, &site); err != nil {
+		return nil, err
+	}
+	return &site, nil
+}
+
+// ListSites 获取站点列表
+func (c *Client) ListSites(ctx context.Context) ([]Site, error) {
+	var sites []Site
+	if _, err := c.do(ctx, http.MethodGet, "/sites", nil, nil, &sites); err != nil {
+		return nil, err
+	}
+	return sites, nil
+}
+
+// DeleteSite 删除站点
+func (c *Client) DeleteSite(ctx context.Context, id uint) error {
+	_, err := c.do(ctx, http.MethodDelete, idPath("/sites", id), nil, nil, nil)
+	return err
+}
+
+// SetSiteTheme 设置站点主题，返回站点和加入队列等待重新生成的文章数
+func (c *Client) SetSiteTheme(ctx context.Context, id uint, theme string) (*Site, int, error) {
+	var result struct {
+		Site   Site `json:"site"`
+		Queued int  `json:"queued"`
+	}
+	if _, err := c.do(ctx, http.MethodPut, idPath("/sites", id)+"/theme", nil, map[string]string{"theme": theme}, &result); err != nil {
+		return nil, 0, err
+	}
+	return &result.Site, result.Queued, nil
+}
+
+// SetSiteDefaultTTL 设置站点文章的默认有效期（小时），0 表示使用全局配置
+func (c *Client) SetSiteDefaultTTL(ctx context.Context, id uint, hours int) (*Site, error) {
+	var site Site
+	body := map[string]int{"default_ttl_hours": hours}
+	if _, err := c.do(ctx, http.MethodPut, idPath("/sites", id)+"/default-ttl", nil, body, &site); err != nil {
+		return nil, err
+	}
+	return &site, nil
+}
+
+// CreateRedirect 创建跳转
+func (c *Client) CreateRedirect(ctx context.Context, input RedirectCreate) (*Redirect, error) {
+	var redirect Redirect
+	if _, err := c.do(ctx, http.MethodPost, "/redirects", nil, input, &redirect); err != nil {
+		return nil, err
+	}
+	return &redirect, nil
+}
+
+// ListRedirects 获取跳转列表
+func (c *Client) ListRedirects(ctx context.Context) ([]Redirect, error) {
+	var redirects []Redirect
+	if _, err := c.do(ctx, http.MethodGet, "/redirects", nil, nil, &redirects); err != nil {
+		return nil, err
+	}
+	return redirects, nil
+}
+
+// DeleteRedirect 删除跳转
+func (c *Client) DeleteRedirect(ctx context.Context, id uint) error {
+	_, err := c.do(ctx, http.MethodDelete, idPath("/redirects", id), nil, nil, nil)
+	return err
+}
+
+// CreateBlueprint 创建文章模板
+func (c *Client) CreateBlueprint(ctx context.Context, input BlueprintInput) (*Blueprint, error) {
+	var blueprint Blueprint
+	if _, err := c.do(ctx, http.MethodPost, "/blueprints", nil, input, &blueprint); err != nil {
+		return nil, err
+	}
+	return &blueprint, nil
+}
+
+// ListBlueprints 获取文章模板列表
+func (c *Client) ListBlueprints(ctx context.Context) ([]Blueprint, error) {
+	var blueprints []Blueprint
+	if _, err := c.do(ctx, http.MethodGet, "/blueprints", nil, nil, &blueprints); err != nil {
+		return nil, err
+	}
+	return blueprints, nil
+}
+
+// GetBlueprint 获取文章模板
+func (c *Client) GetBlueprint(ctx context.Context, id uint) (*Blueprint, error) {
+	var blueprint Blueprint
+	if _, err := c.do(ctx, http.MethodGet, idPath("/blueprints", id), nil, nil, &blueprint); err != nil {
+		return nil, err
+	}
+	return &blueprint, nil
+}
+
+// UpdateBlueprint 更新文章模板
+func (c *Client) UpdateBlueprint(ctx context.Context, id uint, input BlueprintInput) (*Blueprint, error) {
+	var blueprint Blueprint
+	if _, err := c.do(ctx, http.MethodPut, idPath("/blueprints", id), nil, input, &blueprint); err != nil {
+		return nil, err
+	}
+	return &blueprint, nil
+}
+
+// DeleteBlueprint 删除文章模板
+func (c *Client) DeleteBlueprint(ctx context.Context, id uint) error {
+	_, err := c.do(ctx, http.MethodDelete, idPath("/blueprints", id), nil, nil, nil)
+	return err
+}
+
+// RenderBlueprint 用变量渲染文章模板，返回生成的标题和内容
+func (c *Client) RenderBlueprint(ctx context.Context, id uint, variables map[string]string) (string, string, error) {
+	var result struct {
+		Title   string `json:"title"`
+		Content string `json:"content"`
+	}
+	body := map[string]interface{}{"variables": variables}
+	if _, err := c.do(ctx, http.MethodPost, idPath("/blueprints", id)+"/render", nil, body, &result); err != nil {
+		return "", "", err
+	}
+	return result.Title, result.Content, nil
+}
+
+// ListThemes 获取可用主题列表
+func (c *Client) ListThemes(ctx context.Context) (*Themes, error) {
+	var themes Themes
+	if _, err := c.do(ctx, http.MethodGet, "/themes", nil, nil, &themes); err != nil {
+		return nil, err
+	}
+	return &themes, nil
+}
+
+// ValidateTheme 校验主题模板，校验失败时返回错误
+func (c *Client) ValidateTheme(ctx context.Context, name string) error {
+	_, err := c.do(ctx, http.MethodPost, "/themes/"+url.PathEscape(name)+"/validate", nil, nil, nil)
+	return err
+}
+
+// ListSchedulerJobs 获取定时任务及最近一次执行记录
+func (c *Client) ListSchedulerJobs(ctx context.Context) ([]SchedulerJob, error) {
+	var jobs []SchedulerJob
+	if _, err := c.do(ctx, http.MethodGet, "/scheduler/jobs", nil, nil, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// RunSchedulerJob 立即执行定时任务，任务在服务器的后台任务队列中异步执行
+func (c *Client) RunSchedulerJob(ctx context.Context, name string) error {
+	_, err := c.do(ctx, http.MethodPost, "/scheduler/jobs/"+url.PathEscape(name)+"/run", nil, nil, nil)
+	return err
+}
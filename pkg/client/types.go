@@ -0,0 +1,307 @@
+package client
+
+import "time"
+
+// Article 文章
+type Article struct {
+	ID           string     `json:"id"`
+	Title        string     `json:"title"`
+	Content      string     `json:"content"`
+	SiteID       uint       `json:"site_id"`
+	Slug         string     `json:"slug"`
+	Status       string     `json:"status"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	DomainID     *uint      `json:"domain_id"`
+	Theme        string     `json:"theme"`
+	Visibility   string     `json:"visibility"`
+	ExpiryAction string     `json:"expiry_action"`
+	RedirectURL  string     `json:"redirect_url"`
+	WarnedAt     *time.Time `json:"warned_at"`
+	Description  string     `json:"description"`
+	CanonicalURL string     `json:"canonical_url"`
+	CoverImage   string     `json:"cover_image"`
+	Author       string     `json:"author"`
+	OGTitle      string     `json:"og_title"`
+	TwitterCard  string     `json:"twitter_card"`
+	Version      int        `json:"version"` // 每次修改加1，用于 UpdateArticleIfMatch 等
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// ArticleMeta 文章的SEO和分享卡片字段，为nil的字段不修改，空字符串表示清除
+type ArticleMeta struct {
+	Description  *string `json:"description,omitempty"`
+	CanonicalURL *string `json:"canonical_url,omitempty"`
+	CoverImage   *string `json:"cover_image,omitempty"`
+	Author       *string `json:"author,omitempty"`
+	OGTitle      *string `json:"og_title,omitempty"`
+	TwitterCard  *string `json:"twitter_card,omitempty"`
+}
+
+// ArticleCreate 创建文章的请求
+type ArticleCreate struct {
+	Title     string     `json:"title,omitempty"`   // 使用文章模板时可省略
+	Content   string     `json:"content,omitempty"` // 使用文章模板时可省略
+	Slug      string     `json:"slug,omitempty"`
+	Status    string     `json:"status,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // 为空时使用默认有效期
+	Permanent bool       `json:"permanent,omitempty"`  // 不使用默认有效期，文章不过期
+	DomainID  *uint      `json:"domain_id,omitempty"`
+	Theme     string     `json:"theme,omitempty"`
+	SiteID    *uint      `json:"site_id,omitempty"`
+
+	Visibility string `json:"visibility,omitempty"`
+	Password   string `json:"password,omitempty"`
+
+	ExpiryAction string `json:"expiry_action,omitempty"`
+	RedirectURL  string `json:"redirect_url,omitempty"`
+
+	BlueprintID *uint             `json:"blueprint_id,omitempty"`
+	Variables   map[string]string `json:"variables,omitempty"`
+
+	ArticleMeta
+}
+
+// ArticleUpdate 更新文章的请求，省略的字段不修改
+type ArticleUpdate struct {
+	Title     string     `json:"title,omitempty"`
+	Content   string     `json:"content,omitempty"`
+	Slug      string     `json:"slug,omitempty"`
+	Status    string     `json:"status,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	DomainID  *uint      `json:"domain_id,omitempty"`
+	Theme     string     `json:"theme,omitempty"`
+
+	Visibility string `json:"visibility,omitempty"`
+	Password   string `json:"password,omitempty"`
+
+	ExpiryAction string `json:"expiry_action,omitempty"`
+	RedirectURL  string `json:"redirect_url,omitempty"`
+
+	ArticleMeta
+}
+
+// ArticlePatch 部分更新文章的请求，键为字段名（与 ArticleUpdate 的JSON字段相同），
+// 值为nil时清除该字段，例如 ArticlePatch{"expires_at": nil} 取消过期时间
+type ArticlePatch map[string]interface{}
+
+// BulkRequest 批量操作文章的请求，IDs 和 Filter 只能指定一个
+type BulkRequest struct {
+	Action      string      `json:"action"` // publish, unpublish, archive, extend, delete
+	IDs         []string    `json:"ids,omitempty"`
+	Filter      *BulkFilter `json:"filter,omitempty"`
+	ExtendHours int         `json:"extend_hours,omitempty"` // Action 为 extend 时必填
+	Atomic      bool        `json:"atomic,omitempty"`       // 任何一篇失败时全部回滚
+}
+
+// BulkFilter 按条件选择文章，条件之间为“且”
+type BulkFilter struct {
+	Status        string     `json:"status,omitempty"`
+	ExpiresBefore *time.Time `json:"expires_before,omitempty"`
+}
+
+// BulkReport 批量操作的结果
+type BulkReport struct {
+	Action    string     `json:"action"`
+	Atomic    bool       `json:"atomic"`
+	Total     int        `json:"total"`
+	Succeeded int        `json:"succeeded"`
+	Failed    int        `json:"failed"`
+	Results   []BulkItem `json:"results"`
+}
+
+// BulkItem 单篇文章的结果，Code 为 aborted 表示因原子操作中其他文章失败而未修改
+type BulkItem struct {
+	ID      string            `json:"id"`
+	Success bool              `json:"success"`
+	Status  string            `json:"status,omitempty"`
+	Version int               `json:"version,omitempty"`
+	Code    string            `json:"code,omitempty"`
+	Error   string            `json:"error,omitempty"`
+	Errors  map[string]string `json:"errors,omitempty"`
+}
+
+// ListOptions 文章列表的查询条件
+type ListOptions struct {
+	Page   int      // 页码方式使用，从1开始
+	Limit  int      // 每页数量，最大100
+	Status string   // 按状态过滤
+	Fields []string // 只返回的字段
+}
+
+// ArticlePage 按页码获取的文章列表
+type ArticlePage struct {
+	Articles []Article `json:"articles"`
+	Total    int64     `json:"total"`
+	Page     int       `json:"page"`
+	Limit    int       `json:"limit"`
+}
+
+// TrashedArticle 回收站中的文章
+type TrashedArticle struct {
+	Article
+	DeletedAt time.Time  `json:"deleted_at"`
+	PurgeAt   *time.Time `json:"purge_at"` // 为空时不会被自动彻底删除
+}
+
+// TrashPage 回收站中的文章列表
+type TrashPage struct {
+	Articles []TrashedArticle `json:"articles"`
+	Total    int64            `json:"total"`
+	Page     int              `json:"page"`
+	Limit    int              `json:"limit"`
+}
+
+// ArticleCursorPage 按游标获取的文章列表，NextCursor为空表示没有更多文章
+type ArticleCursorPage struct {
+	Articles   []Article `json:"articles"`
+	Limit      int       `json:"limit"`
+	NextCursor string    `json:"next_cursor"`
+}
+
+// Preview 草稿预览链接
+type Preview struct {
+	ID        uint       `json:"id"`
+	ArticleID string     `json:"article_id"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// PreviewLink 预览链接及其访问地址
+type PreviewLink struct {
+	Preview Preview `json:"preview"`
+	URL     string  `json:"url"`
+}
+
+// APIKey API密钥
+type APIKey struct {
+	ID              uint       `json:"id"`
+	Name            string     `json:"name"`
+	Key             string     `json:"key"` // 只在创建时返回
+	IsActive        bool       `json:"is_active"`
+	LastUsedAt      *time.Time `json:"last_used_at"`
+	ExpiresAt       *time.Time `json:"expires_at"`
+	Permissions     string     `json:"permissions"`
+	SiteID          *uint      `json:"site_id"`
+	DefaultTTLHours int        `json:"default_ttl_hours"` // 通过此密钥创建的文章的默认有效期（小时），0 表示使用站点设置
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// APIKeyCreate 创建API密钥的请求
+type APIKeyCreate struct {
+	Name        string     `json:"name"`
+	Permissions string     `json:"permissions,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	SiteID      *uint      `json:"site_id,omitempty"`
+
+	DefaultTTLHours int `json:"default_ttl_hours,omitempty"`
+}
+
+// Domain 自定义域名
+type Domain struct {
+	ID            uint      `json:"id"`
+	Host          string    `json:"host"`
+	SiteID        *uint     `json:"site_id"`
+	CertificateID *uint     `json:"certificate_id"`
+	IsActive      bool      `json:"is_active"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Site 站点
+type Site struct {
+	ID              uint      `json:"id"`
+	Name            string    `json:"name"`
+	Theme           string    `json:"theme"`
+	StoragePrefix   string    `json:"storage_prefix"`
+	DefaultTTLHours int       `json:"default_ttl_hours"` // 通过接口创建的文章的默认有效期（小时），0 表示使用全局配置
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// SiteCreate 创建站点的请求
+type SiteCreate struct {
+	Name          string `json:"name"`
+	Domain        string `json:"domain,omitempty"`
+	Theme         string `json:"theme,omitempty"`
+	StoragePrefix string `json:"storage_prefix,omitempty"`
+}
+
+// ArticleRevive 恢复已过期文章的请求，最多指定一个字段，都为空时使用默认有效期
+type ArticleRevive struct {
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	ExtendHours int        `json:"extend_hours,omitempty"` // 从现在起的有效期
+	Permanent   bool       `json:"permanent,omitempty"`
+}
+
+// Redirect 路径跳转
+type Redirect struct {
+	ID         uint      `json:"id"`
+	SiteID     uint      `json:"site_id"`
+	SourcePath string    `json:"source_path"`
+	Target     string    `json:"target"`
+	StatusCode int       `json:"status_code"`
+	HitCount   int64     `json:"hit_count"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// RedirectCreate 创建跳转的请求
+type RedirectCreate struct {
+	SourcePath string `json:"source_path"`
+	Target     string `json:"target"`
+	StatusCode int    `json:"status_code,omitempty"` // 301（默认）, 302, 307, 308
+	SiteID     *uint  `json:"site_id,omitempty"`
+}
+
+// Blueprint 文章模板
+type Blueprint struct {
+	ID           uint      `json:"id"`
+	SiteID       uint      `json:"site_id"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	Title        string    `json:"title"`
+	Content      string    `json:"content"`
+	Theme        string    `json:"theme"`
+	Placeholders []string  `json:"placeholders"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// BlueprintInput 创建或更新文章模板的请求，更新时为空的字段不修改
+type BlueprintInput struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Content     string `json:"content,omitempty"`
+	Theme       string `json:"theme,omitempty"`
+	SiteID      *uint  `json:"site_id,omitempty"`
+}
+
+// Themes 可用主题
+type Themes struct {
+	Themes  []string `json:"themes"`
+	Default string   `json:"default"`
+}
+
+// SchedulerRun 定时任务的一次执行记录
+type SchedulerRun struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Holder     string     `json:"holder"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	Items      int        `json:"items"`
+	Error      string     `json:"error"`
+	Skipped    bool       `json:"skipped"` // 同一任务正在其他节点执行，本次未执行
+}
+
+// SchedulerJob 定时任务及最近一次执行记录
+type SchedulerJob struct {
+	Name        string        `json:"name"`
+	Schedule    string        `json:"schedule"` // 为空时只能手动执行
+	Description string        `json:"description"`
+	LastRun     *SchedulerRun `json:"last_run"`
+}
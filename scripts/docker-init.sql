@@ -23,14 +23,15 @@ CREATE TABLE articles (
     id VARCHAR(36) PRIMARY KEY COMMENT 'UUID主键',
     title VARCHAR(255) NOT NULL COMMENT '文章标题',
     content LONGTEXT COMMENT '文章内容（支持HTML）',
-    slug VARCHAR(255) NOT NULL COMMENT 'URL友好的标识符',
+    site_id INT UNSIGNED NOT NULL DEFAULT 0 COMMENT '所属站点，0为默认站点',
+    slug VARCHAR(255) NOT NULL COMMENT 'URL友好的标识符（站点内唯一）',
     status VARCHAR(20) DEFAULT 'draft' COMMENT '文章状态：draft, published, archived',
     expires_at DATETIME(3) NULL COMMENT '过期时间',
     created_at DATETIME(3) DEFAULT CURRENT_TIMESTAMP(3) COMMENT '创建时间',
     updated_at DATETIME(3) DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3) COMMENT '更新时间',
     deleted_at DATETIME(3) NULL COMMENT '删除时间（软删除）',
     
-    UNIQUE INDEX uni_articles_slug (slug),
+    UNIQUE INDEX idx_articles_site_slug (site_id, slug),
     INDEX idx_articles_status (status),
     INDEX idx_articles_created_at (created_at),
     INDEX idx_articles_expires_at (expires_at),
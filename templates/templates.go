@@ -0,0 +1,7 @@
+// Package templates 将内置的HTML模板嵌入到二进制文件中，作为默认主题和后台模板
+package templates
+
+import "embed"
+
+//go:embed *.html
+var FS embed.FS